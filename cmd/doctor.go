@@ -0,0 +1,183 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-signer-notation-plugin/internal/client"
+	"github.com/aws/aws-signer-notation-plugin/internal/signer"
+	awsplugin "github.com/aws/aws-signer-notation-plugin/plugin"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	awssigner "github.com/aws/aws-sdk-go-v2/service/signer"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"github.com/aws/smithy-go"
+
+	"github.com/notaryproject/notation-plugin-framework-go/plugin"
+)
+
+// doctorCommand is a diagnostics command of the plugin executable which isn't part of the notation plugin contract.
+const doctorCommand = "doctor"
+
+// Exit codes of doctor command.
+const (
+	exitCodeOK             = 0
+	exitCodeUsage          = 2
+	exitCodeConfig         = 3
+	exitCodeCredentials    = 4
+	exitCodeSigningProfile = 5
+	exitCodeNetwork        = 6
+)
+
+const errMsgMissingRegion = "AWS region is not configured, use aws-region plugin config or AWS_REGION environment variable"
+
+// pluginConfigFlag collects repeated key=value flags into plugin config.
+type pluginConfigFlag map[string]string
+
+func (f pluginConfigFlag) String() string {
+	var pairs []string
+	for k, v := range f {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+func (f pluginConfigFlag) Set(value string) error {
+	k, v, ok := strings.Cut(value, "=")
+	if !ok || k == "" {
+		return fmt.Errorf("%q isn't in key=value format", value)
+	}
+	f[k] = v
+	return nil
+}
+
+// runDoctor resolves the AWS configuration the same way plugin does, prints the effective region, credential profile,
+// AWS Signer endpoint and caller identity, optionally checks the given signing profile and returns the exit code.
+func runDoctor(ctx context.Context, args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet(doctorCommand, flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	pluginConfig := pluginConfigFlag{}
+	flags.Var(pluginConfig, "plugin-config", "plugin config in `key=value` format, can be used multiple times")
+	keyID := flags.String("key-id", "", "signing profile ARN or signing profile version ARN to check using GetSigningProfile API")
+	flags.Usage = func() {
+		_, _ = fmt.Fprintf(stderr, "Usage: notation-%s %s [--plugin-config key=value]... [--key-id arn]\n\n"+
+			"Checks the AWS configuration used by the plugin. Exit codes: %d success, %d invalid arguments, "+
+			"%d invalid configuration, %d invalid credentials, %d signing profile check failure, %d network failure.\n\n",
+			awsplugin.Name, doctorCommand, exitCodeOK, exitCodeUsage, exitCodeConfig, exitCodeCredentials, exitCodeSigningProfile, exitCodeNetwork)
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return exitCodeUsage
+	}
+	if flags.NArg() > 0 {
+		_, _ = fmt.Fprintf(stderr, "unexpected argument %q\n", flags.Arg(0))
+		flags.Usage()
+		return exitCodeUsage
+	}
+
+	report := func(name, value string) {
+		_, _ = fmt.Fprintf(stdout, "%-20s %s\n", name+":", value)
+	}
+	fail := func(name string, err error, exitCode int) int {
+		var plgErr *plugin.Error
+		if errors.As(err, &plgErr) {
+			err = errors.New(plgErr.Message)
+		}
+		_, _ = fmt.Fprintf(stdout, "%-20s FAILED: %v\n", name+":", err)
+		return exitCode
+	}
+
+	cfg, err := client.LoadConfig(ctx, pluginConfig)
+	if err != nil {
+		return fail("Configuration", err, exitCodeConfig)
+	}
+	if cfg.Region == "" {
+		return fail("Region", errors.New(errMsgMissingRegion), exitCodeConfig)
+	}
+	report("Region", cfg.Region)
+	report("Credential profile", getSharedConfigProfile(cfg))
+	endpoint, err := getSignerEndpoint(ctx, cfg)
+	if err != nil {
+		return fail("Signer endpoint", err, exitCodeConfig)
+	}
+	report("Signer endpoint", endpoint)
+
+	creds, err := cfg.Credentials.Retrieve(ctx)
+	if err != nil {
+		return fail("Credentials", err, exitCodeCredentials)
+	}
+	report("Credentials source", creds.Source)
+	identity, err := sts.NewFromConfig(cfg).GetCallerIdentity(ctx, &sts.GetCallerIdentityInput{})
+	if err != nil {
+		var apiErr smithy.APIError
+		if !errors.As(err, &apiErr) {
+			return fail("Caller identity", err, exitCodeNetwork)
+		}
+		return fail("Caller identity", err, exitCodeCredentials)
+	}
+	report("Caller identity", aws.ToString(identity.Arn))
+
+	if *keyID != "" {
+		res, err := signer.New(awssigner.NewFromConfig(cfg)).DescribeKey(ctx, &plugin.DescribeKeyRequest{
+			ContractVersion: plugin.ContractVersion,
+			KeyID:           *keyID,
+		})
+		if err != nil {
+			return fail("Signing profile", err, exitCodeSigningProfile)
+		}
+		report("Signing profile", fmt.Sprintf("%s (key spec %s)", res.KeyID, res.KeySpec))
+	}
+
+	_, _ = fmt.Fprintln(stdout, "All checks passed.")
+	return exitCodeOK
+}
+
+// getSharedConfigProfile returns the shared config profile used to load the AWS configuration.
+func getSharedConfigProfile(cfg aws.Config) string {
+	for _, source := range cfg.ConfigSources {
+		if sharedConfig, ok := source.(config.SharedConfig); ok && sharedConfig.Profile != "" {
+			return sharedConfig.Profile
+		}
+	}
+	return "default"
+}
+
+// getSignerEndpoint returns the AWS Signer endpoint overridden through plugin config, or the default endpoint for
+// the configured region.
+func getSignerEndpoint(ctx context.Context, cfg aws.Config) (string, error) {
+	if cfg.EndpointResolverWithOptions != nil {
+		endpoint, err := cfg.EndpointResolverWithOptions.ResolveEndpoint(awssigner.ServiceID, cfg.Region)
+		if err == nil {
+			return endpoint.URL, nil
+		}
+		var notFound *aws.EndpointNotFoundError
+		if !errors.As(err, &notFound) {
+			return "", err
+		}
+	}
+	endpoint, err := awssigner.NewDefaultEndpointResolverV2().ResolveEndpoint(ctx, awssigner.EndpointParameters{Region: aws.String(cfg.Region)})
+	if err != nil {
+		return "", err
+	}
+	return endpoint.URI.String(), nil
+}
@@ -0,0 +1,140 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunDoctor(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("AWS_CONFIG_FILE", filepath.Join(home, "config"))
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", filepath.Join(home, "credentials"))
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKIDEXAMPLE")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+	t.Setenv("AWS_EC2_METADATA_DISABLED", "true")
+	t.Setenv("AWS_REGION", "")
+	t.Setenv("AWS_PROFILE", "")
+
+	signerServer := newFakeSigner(t)
+	profileArn, _ := signerServer.AddSigningProfile("DoctorProfile")
+	stsServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/xml")
+		_, _ = w.Write([]byte(`<GetCallerIdentityResponse xmlns="https://sts.amazonaws.com/doc/2011-06-15/">
+  <GetCallerIdentityResult>
+    <Arn>arn:aws:iam::123456789012:user/ci</Arn>
+    <UserId>AIDAEXAMPLE</UserId>
+    <Account>123456789012</Account>
+  </GetCallerIdentityResult>
+  <ResponseMetadata><RequestId>request-id</RequestId></ResponseMetadata>
+</GetCallerIdentityResponse>`))
+	}))
+	defer stsServer.Close()
+	invalidTokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/xml")
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte(`<ErrorResponse xmlns="https://sts.amazonaws.com/doc/2011-06-15/">
+  <Error><Type>Sender</Type><Code>InvalidClientTokenId</Code><Message>The security token included in the request is invalid.</Message></Error>
+  <RequestId>request-id</RequestId>
+</ErrorResponse>`))
+	}))
+	defer invalidTokenServer.Close()
+
+	endpointArgs := []string{
+		"--plugin-config", "aws-region=us-west-2",
+		"--plugin-config", "aws-signer-endpoint-url=" + signerServer.URL,
+		"--plugin-config", "aws-sts-endpoint-url=" + stsServer.URL,
+	}
+	tests := map[string]struct {
+		args     []string
+		exitCode int
+		output   []string
+	}{
+		"success": {
+			args:     endpointArgs,
+			exitCode: exitCodeOK,
+			output: []string{
+				"Region:              us-west-2\n",
+				"Credential profile:  default\n",
+				"Signer endpoint:     " + signerServer.URL + "\n",
+				"Credentials source:  EnvConfigCredentials\n",
+				"Caller identity:     arn:aws:iam::123456789012:user/ci\n",
+				"All checks passed.\n",
+			},
+		},
+		"defaultEndpoint": {
+			args:     []string{"--plugin-config", "aws-region=us-west-2", "--plugin-config", "aws-sts-endpoint-url=" + stsServer.URL},
+			exitCode: exitCodeOK,
+			output:   []string{"Signer endpoint:     https://signer.us-west-2.amazonaws.com\n"},
+		},
+		"signingProfile": {
+			args:     append([]string{"--key-id", profileArn}, endpointArgs...),
+			exitCode: exitCodeOK,
+			output:   []string{"Signing profile:     " + profileArn + " (key spec EC-384)\n"},
+		},
+		"unknownSigningProfile": {
+			args:     append([]string{"--key-id", "arn:aws:signer:us-west-2:123456789012:/signing-profiles/Unknown"}, endpointArgs...),
+			exitCode: exitCodeSigningProfile,
+			output:   []string{"Signing profile:     FAILED: Failed to call AWSSigner. Error: signing profile Unknown not found. RequestID: "},
+		},
+		"invalidArgument": {
+			args:     []string{"--unknown"},
+			exitCode: exitCodeUsage,
+		},
+		"unexpectedArgument": {
+			args:     []string{"unexpected"},
+			exitCode: exitCodeUsage,
+		},
+		"malformedPluginConfig": {
+			args:     []string{"--plugin-config", "aws-region"},
+			exitCode: exitCodeUsage,
+		},
+		"invalidPluginConfig": {
+			args:     []string{"--plugin-config", "aws-max-attempts=0"},
+			exitCode: exitCodeConfig,
+			output:   []string{"Configuration:       FAILED: invalid value \"0\" for plugin config \"aws-max-attempts\", expected a positive integer.\n"},
+		},
+		"missingRegion": {
+			exitCode: exitCodeConfig,
+			output:   []string{"Region:              FAILED: AWS region is not configured, use aws-region plugin config or AWS_REGION environment variable\n"},
+		},
+		"invalidCredentials": {
+			args:     []string{"--plugin-config", "aws-region=us-west-2", "--plugin-config", "aws-sts-endpoint-url=" + invalidTokenServer.URL},
+			exitCode: exitCodeCredentials,
+			output:   []string{"Caller identity:     FAILED: ", "InvalidClientTokenId"},
+		},
+		"networkFailure": {
+			args:     []string{"--plugin-config", "aws-region=us-west-2", "--plugin-config", "aws-sts-endpoint-url=http://127.0.0.1:1", "--plugin-config", "aws-max-attempts=1"},
+			exitCode: exitCodeNetwork,
+			output:   []string{"Caller identity:     FAILED: "},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			exitCode := runDoctor(context.TODO(), test.args, &stdout, &stderr)
+			assert.Equal(t, test.exitCode, exitCode, "exit code mismatch. stdout: %s, stderr: %s", stdout.String(), stderr.String())
+			for _, output := range test.output {
+				assert.Contains(t, stdout.String(), output, "output mismatch")
+			}
+		})
+	}
+}
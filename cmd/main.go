@@ -17,6 +17,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"strconv"
 
 	"github.com/aws/aws-signer-notation-plugin/internal/logger"
 	"github.com/aws/aws-signer-notation-plugin/plugin"
@@ -24,16 +25,33 @@ import (
 	"github.com/notaryproject/notation-plugin-framework-go/cli"
 )
 
-const debugFlag = "AWS_SIGNER_NOTATION_PLUGIN_DEBUG"
+const (
+	debugFlag         = "AWS_SIGNER_NOTATION_PLUGIN_DEBUG"
+	logFormatFlag     = "AWS_SIGNER_NOTATION_PLUGIN_LOG_FORMAT"
+	logPathFlag       = "AWS_SIGNER_NOTATION_PLUGIN_LOG_PATH"
+	logLevelFlag      = "AWS_SIGNER_NOTATION_PLUGIN_LOG_LEVEL"
+	logMaxSizeFlag    = "AWS_SIGNER_NOTATION_PLUGIN_LOG_MAX_SIZE_MB"
+	logMaxBackupsFlag = "AWS_SIGNER_NOTATION_PLUGIN_LOG_MAX_BACKUPS"
+	logRedactionFlag  = "AWS_SIGNER_NOTATION_PLUGIN_LOG_REDACTION"
+)
 
 func main() {
-	awsPlugin := plugin.NewAWSSignerForCLI()
 	ctx := context.Background()
+	if len(os.Args) > 1 && os.Args[1] == doctorCommand {
+		os.Exit(runDoctor(ctx, os.Args[2:], os.Stdout, os.Stderr))
+	}
+
+	awsPlugin := plugin.NewAWSSignerForCLI()
 
 	var pluginCli *cli.CLI
 	var err error
-	if os.Getenv(debugFlag) == "true" {
-		log, logErr := logger.New()
+	if os.Getenv(debugFlag) == "true" || os.Getenv(logLevelFlag) != "" {
+		logOpts, logErr := getLoggerOptions()
+		if logErr != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "invalid logging configuration: %v\n", logErr)
+			os.Exit(100)
+		}
+		log, logErr := logger.New(logOpts...)
 		if logErr != nil {
 			os.Exit(100)
 		}
@@ -49,3 +67,45 @@ func main() {
 	}
 	pluginCli.Execute(ctx, os.Args)
 }
+
+// getLoggerOptions returns the logger options configured through environment variables.
+func getLoggerOptions() ([]logger.Option, error) {
+	var logOpts []logger.Option
+	if os.Getenv(logFormatFlag) == "json" {
+		logOpts = append(logOpts, logger.WithJSONFormat())
+	}
+	if path := os.Getenv(logPathFlag); path != "" {
+		logOpts = append(logOpts, logger.WithPath(path))
+	}
+	if levelName := os.Getenv(logLevelFlag); levelName != "" {
+		level, err := logger.ParseLevel(levelName)
+		if err != nil {
+			return nil, err
+		}
+		logOpts = append(logOpts, logger.WithLevel(level))
+	}
+	switch val := os.Getenv(logRedactionFlag); val {
+	case "", "true":
+	case "false":
+		logOpts = append(logOpts, logger.WithoutRedaction())
+	default:
+		return nil, fmt.Errorf("%s must be either true or false, but found %q", logRedactionFlag, val)
+	}
+
+	maxSize, maxBackups := int64(logger.DefaultMaxSize), logger.DefaultMaxBackups
+	if val := os.Getenv(logMaxSizeFlag); val != "" {
+		sizeMB, err := strconv.ParseUint(val, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("%s must be a non-negative integer, but found %q", logMaxSizeFlag, val)
+		}
+		maxSize = int64(sizeMB) * 1024 * 1024
+	}
+	if val := os.Getenv(logMaxBackupsFlag); val != "" {
+		backups, err := strconv.ParseUint(val, 10, 16)
+		if err != nil {
+			return nil, fmt.Errorf("%s must be a non-negative integer, but found %q", logMaxBackupsFlag, val)
+		}
+		maxBackups = int(backups)
+	}
+	return append(logOpts, logger.WithRotation(maxSize, maxBackups)), nil
+}
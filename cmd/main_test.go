@@ -0,0 +1,529 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/sha512"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-signer-notation-plugin/internal/fakesigner"
+
+	"github.com/notaryproject/notation-plugin-framework-go/plugin"
+	"github.com/stretchr/testify/assert"
+)
+
+// e2eFlag makes the test binary behave as the plugin executable, so that end-to-end tests can drive main through
+// the notation plugin CLI protocol.
+const e2eFlag = "AWS_SIGNER_NOTATION_PLUGIN_E2E"
+
+const testDescriptor = `{"mediaType":"application/vnd.oci.image.manifest.v1+json","digest":"sha256:2f3a23b6373afb134ddcd864be8e037e34a662d090d33ee849471ff73c873345","size":528}`
+
+func TestMain(m *testing.M) {
+	if os.Getenv(e2eFlag) == "true" {
+		main()
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+func TestE2E_GenerateEnvelope(t *testing.T) {
+	server := newFakeSigner(t)
+	profileArn, profileVersionArn := server.AddSigningProfile("E2EProfile")
+
+	var res plugin.GenerateEnvelopeResponse
+	err := runPlugin(t, plugin.CommandGenerateEnvelope, getGenerateEnvelopeRequest(server, profileArn), &res)
+	if !assert.NoError(t, err, "generate-envelope failed") {
+		return
+	}
+	assert.Equal(t, "application/jose+json", res.SignatureEnvelopeType, "SignatureEnvelopeType mismatch")
+	assert.Equal(t, profileVersionArn, res.Annotations["com.amazonaws.signer.signingProfileVersion"], "signingProfileVersion annotation mismatch")
+
+	env := parseJws(t, res.SignatureEnvelope)
+	assert.JSONEq(t, `{"targetArtifact":`+testDescriptor+`}`, string(env.payload), "payload mismatch")
+	assert.Equal(t, profileVersionArn, env.header["com.amazonaws.signer.signingProfileVersion"], "signingProfileVersion header mismatch")
+
+	roots := x509.NewCertPool()
+	roots.AddCert(env.certs[len(env.certs)-1])
+	intermediates := x509.NewCertPool()
+	for _, cert := range env.certs[1 : len(env.certs)-1] {
+		intermediates.AddCert(cert)
+	}
+	_, err = env.certs[0].Verify(x509.VerifyOptions{Roots: roots, Intermediates: intermediates, KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning}})
+	assert.NoError(t, err, "certificate chain verification failed")
+}
+
+func TestE2E_GenerateEnvelope_PinnedProfileVersion(t *testing.T) {
+	server := newFakeSigner(t)
+	_, oldProfileVersionArn := server.AddSigningProfile("E2EProfile")
+	_, profileVersionArn := server.AddSigningProfile("E2EProfile")
+
+	var res plugin.GenerateEnvelopeResponse
+	err := runPlugin(t, plugin.CommandGenerateEnvelope, getGenerateEnvelopeRequest(server, profileVersionArn), &res)
+	assert.NoError(t, err, "generate-envelope failed for active signing profile version")
+
+	err = runPlugin(t, plugin.CommandGenerateEnvelope, getGenerateEnvelopeRequest(server, oldProfileVersionArn), &res)
+	var plgErr *plugin.Error
+	if assert.ErrorAs(t, err, &plgErr) {
+		assert.Equal(t, plugin.ErrorCodeValidation, plgErr.ErrCode, "error code mismatch")
+		assert.Contains(t, plgErr.Message, "was requested but AWS Signer signed with signing profile version", "error message mismatch")
+	}
+}
+
+func TestE2E_GenerateEnvelope_UnknownProfile(t *testing.T) {
+	server := newFakeSigner(t)
+	req := getGenerateEnvelopeRequest(server, "arn:aws:signer:us-west-2:123456789012:/signing-profiles/Unknown")
+
+	err := runPlugin(t, plugin.CommandGenerateEnvelope, req, &plugin.GenerateEnvelopeResponse{})
+	var plgErr *plugin.Error
+	if assert.ErrorAs(t, err, &plgErr) {
+		assert.Equal(t, plugin.ErrorCodeValidation, plgErr.ErrCode, "error code mismatch")
+		assert.Contains(t, plgErr.Message, "signing profile Unknown not found", "error message mismatch")
+	}
+}
+
+func TestE2E_VerifySignature(t *testing.T) {
+	tests := map[string]struct {
+		revoke                func(server *fakesigner.Server, env *jwsEnvelope) []string
+		trustedIdentity       func(profileArn string) string
+		identitySuccess       bool
+		revocationSuccess     bool
+		revocationReasonMatch string
+	}{
+		"notRevoked": {
+			identitySuccess:       true,
+			revocationSuccess:     true,
+			revocationReasonMatch: "Signature is not revoked.",
+		},
+		"untrustedIdentity": {
+			trustedIdentity: func(string) string {
+				return "arn:aws:signer:us-west-2:123456789012:/signing-profiles/OtherProfile"
+			},
+			revocationSuccess:     true,
+			revocationReasonMatch: "Signature is not revoked.",
+		},
+		"revokedProfileVersion": {
+			revoke: func(_ *fakesigner.Server, env *jwsEnvelope) []string {
+				return []string{env.header["com.amazonaws.signer.signingProfileVersion"].(string)}
+			},
+			identitySuccess:       true,
+			revocationReasonMatch: "/signing-profiles/E2EProfile/",
+		},
+		"revokedJob": {
+			revoke: func(_ *fakesigner.Server, env *jwsEnvelope) []string {
+				return []string{env.header["com.amazonaws.signer.signingJob"].(string)}
+			},
+			identitySuccess:       true,
+			revocationReasonMatch: "/signing-jobs/",
+		},
+		"revokedCertificate": {
+			revoke: func(server *fakesigner.Server, _ *jwsEnvelope) []string {
+				certs := server.Certificates()
+				return []string{fakesigner.CertificateHash(certs[0], certs[1])}
+			},
+			identitySuccess:       true,
+			revocationReasonMatch: "Certificate(s) have been revoked.",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			server := newFakeSigner(t)
+			profileArn, _ := server.AddSigningProfile("E2EProfile")
+
+			var res plugin.GenerateEnvelopeResponse
+			if err := runPlugin(t, plugin.CommandGenerateEnvelope, getGenerateEnvelopeRequest(server, profileArn), &res); err != nil {
+				t.Fatalf("generate-envelope failed: %v", err)
+			}
+			env := parseJws(t, res.SignatureEnvelope)
+			if test.revoke != nil {
+				server.Revoke(test.revoke(server, env)...)
+			}
+			trustedIdentity := profileArn
+			if test.trustedIdentity != nil {
+				trustedIdentity = test.trustedIdentity(profileArn)
+			}
+
+			var verifyRes plugin.VerifySignatureResponse
+			err := runPlugin(t, plugin.CommandVerifySignature, getVerifySignatureRequest(server, env, trustedIdentity), &verifyRes)
+			if !assert.NoError(t, err, "verify-signature failed") {
+				return
+			}
+			identityResult := verifyRes.VerificationResults[plugin.CapabilityTrustedIdentityVerifier]
+			assert.Equal(t, test.identitySuccess, identityResult.Success, "trusted identity result mismatch: %s", identityResult.Reason)
+			revocationResult := verifyRes.VerificationResults[plugin.CapabilityRevocationCheckVerifier]
+			assert.Equal(t, test.revocationSuccess, revocationResult.Success, "revocation result mismatch: %s", revocationResult.Reason)
+			assert.Contains(t, revocationResult.Reason, test.revocationReasonMatch, "revocation reason mismatch")
+		})
+	}
+}
+
+func TestE2E_VerifySignature_Provenance(t *testing.T) {
+	tests := map[string]struct {
+		allowedRequesters string
+		identitySuccess   bool
+		identityReason    string
+	}{
+		"allowedRequester": {
+			allowedRequesters: fakesigner.RequestedBy,
+			identitySuccess:   true,
+			identityReason:    "was requested by \"" + fakesigner.RequestedBy + "\".",
+		},
+		"notAllowedRequester": {
+			allowedRequesters: "arn:aws:iam::123456789012:user/Other",
+			identityReason:    "isn't an allowed requester.",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			server := newFakeSigner(t)
+			profileArn, _ := server.AddSigningProfile("E2EProfile")
+
+			var res plugin.GenerateEnvelopeResponse
+			if err := runPlugin(t, plugin.CommandGenerateEnvelope, getGenerateEnvelopeRequest(server, profileArn), &res); err != nil {
+				t.Fatalf("generate-envelope failed: %v", err)
+			}
+			req := getVerifySignatureRequest(server, parseJws(t, res.SignatureEnvelope), profileArn)
+			req.PluginConfig["aws-signer-allowed-requesters"] = test.allowedRequesters
+
+			var verifyRes plugin.VerifySignatureResponse
+			if err := runPlugin(t, plugin.CommandVerifySignature, req, &verifyRes); !assert.NoError(t, err, "verify-signature failed") {
+				return
+			}
+			identityResult := verifyRes.VerificationResults[plugin.CapabilityTrustedIdentityVerifier]
+			assert.Equal(t, test.identitySuccess, identityResult.Success, "trusted identity result mismatch: %s", identityResult.Reason)
+			assert.Contains(t, identityResult.Reason, test.identityReason, "trusted identity reason mismatch")
+		})
+	}
+}
+
+func TestE2E_JSONLogging(t *testing.T) {
+	server := newFakeSigner(t)
+	profileArn, profileVersionArn := server.AddSigningProfile("E2EProfile")
+	configDir := t.TempDir()
+	env := []string{"XDG_CONFIG_HOME=" + configDir, "AWS_SIGNER_NOTATION_PLUGIN_DEBUG=true", "AWS_SIGNER_NOTATION_PLUGIN_LOG_FORMAT=json"}
+
+	var res plugin.GenerateEnvelopeResponse
+	if err := runPlugin(t, plugin.CommandGenerateEnvelope, getGenerateEnvelopeRequest(server, profileArn), &res, env...); err != nil {
+		t.Fatalf("generate-envelope failed: %v", err)
+	}
+	var verifyRes plugin.VerifySignatureResponse
+	if err := runPlugin(t, plugin.CommandVerifySignature, getVerifySignatureRequest(server, parseJws(t, res.SignatureEnvelope), profileArn), &verifyRes, env...); err != nil {
+		t.Fatalf("verify-signature failed: %v", err)
+	}
+
+	logs, err := os.ReadFile(filepath.Join(configDir, "notation-aws-signer", "plugin.log"))
+	if err != nil {
+		t.Fatalf("failed to read log file: %v", err)
+	}
+	invocationIDs := map[string]bool{}
+	var signPayloadEntry, revocationEntry map[string]string
+	for _, line := range strings.Split(strings.TrimSpace(string(logs)), "\n") {
+		var entry map[string]string
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("log entry %q isn't valid JSON: %v", line, err)
+		}
+		invocationIDs[entry["invocationId"]] = true
+		if strings.HasPrefix(entry["message"], "succeeded AWS Signer's SignPayload API call") {
+			signPayloadEntry = entry
+		}
+		if strings.HasPrefix(entry["message"], "succeeded AWS Signer's GetRevocationStatus API call") {
+			revocationEntry = entry
+		}
+	}
+	assert.Len(t, invocationIDs, 2, "expected an invocation ID per plugin execution")
+	if assert.NotNil(t, signPayloadEntry, "SignPayload log entry not found") {
+		assert.Equal(t, "generate-envelope", signPayloadEntry["operation"], "operation mismatch")
+		assert.Equal(t, profileArn, signPayloadEntry["profileArn"], "profileArn mismatch")
+		assert.NotEmpty(t, signPayloadEntry["awsRequestId"], "awsRequestId not found")
+	}
+	if assert.NotNil(t, revocationEntry, "GetRevocationStatus log entry not found") {
+		assert.Equal(t, "verify-signature", revocationEntry["operation"], "operation mismatch")
+		assert.Equal(t, profileVersionArn, revocationEntry["profileArn"], "profileArn mismatch")
+		assert.NotEmpty(t, revocationEntry["awsRequestId"], "awsRequestId not found")
+	}
+}
+
+func TestE2E_LogConfig(t *testing.T) {
+	server := newFakeSigner(t)
+	profileArn, _ := server.AddSigningProfile("E2EProfile")
+	logPath := filepath.Join(t.TempDir(), "logs", "signer.log")
+	env := []string{"AWS_SIGNER_NOTATION_PLUGIN_LOG_LEVEL=info", "AWS_SIGNER_NOTATION_PLUGIN_LOG_PATH=" + logPath}
+
+	var res plugin.GenerateEnvelopeResponse
+	if err := runPlugin(t, plugin.CommandGenerateEnvelope, getGenerateEnvelopeRequest(server, profileArn), &res, env...); err != nil {
+		t.Fatalf("generate-envelope failed: %v", err)
+	}
+
+	logs, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf("failed to read log file: %v", err)
+	}
+	assert.Contains(t, string(logs), "[INFO] Logs from execution of AWS signer plugin version", "info log entry not found")
+	assert.NotContains(t, string(logs), "[DEBUG]", "debug log entry found with info log level")
+}
+
+func TestE2E_LogRedaction(t *testing.T) {
+	server := newFakeSigner(t)
+	profileArn, _ := server.AddSigningProfile("E2EProfile")
+	sessionToken := "E2ESessionToken"
+
+	tests := map[string]struct {
+		redaction string
+		redacted  bool
+	}{
+		"default":          {redacted: true},
+		"redactionEnabled": {redaction: "true", redacted: true},
+		"redactionOff":     {redaction: "false", redacted: false},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			logPath := filepath.Join(t.TempDir(), "plugin.log")
+			env := []string{
+				"AWS_SIGNER_NOTATION_PLUGIN_DEBUG=true",
+				"AWS_SIGNER_NOTATION_PLUGIN_LOG_PATH=" + logPath,
+				"AWS_SIGNER_NOTATION_PLUGIN_LOG_REDACTION=" + tc.redaction,
+				"AWS_SESSION_TOKEN=" + sessionToken,
+			}
+			var res plugin.GenerateEnvelopeResponse
+			if err := runPlugin(t, plugin.CommandGenerateEnvelope, getGenerateEnvelopeRequest(server, profileArn), &res, env...); err != nil {
+				t.Fatalf("generate-envelope failed: %v", err)
+			}
+
+			logs, err := os.ReadFile(logPath)
+			if err != nil {
+				t.Fatalf("failed to read log file: %v", err)
+			}
+			envelope := base64.StdEncoding.EncodeToString(res.SignatureEnvelope)
+			for _, secret := range []string{sessionToken, "Credential=AKIDEXAMPLE", envelope, res.Annotations["com.amazonaws.signer.signingJob"]} {
+				assert.Equal(t, !tc.redacted, strings.Contains(string(logs), secret), "unexpected presence of %q in logs", secret)
+			}
+		})
+	}
+}
+
+func TestGetLoggerOptions(t *testing.T) {
+	tests := map[string]struct {
+		env    map[string]string
+		errMsg string
+	}{
+		"default": {
+			env: map[string]string{},
+		},
+		"allOptions": {
+			env: map[string]string{
+				logPathFlag:       "plugin.log",
+				logLevelFlag:      "WARN",
+				logMaxSizeFlag:    "1",
+				logMaxBackupsFlag: "0",
+				logRedactionFlag:  "false",
+			},
+		},
+		"invalidLevel": {
+			env:    map[string]string{logLevelFlag: "trace"},
+			errMsg: "unsupported log level \"trace\", supported values are debug, info, warn and error",
+		},
+		"invalidMaxSize": {
+			env:    map[string]string{logMaxSizeFlag: "-1"},
+			errMsg: "AWS_SIGNER_NOTATION_PLUGIN_LOG_MAX_SIZE_MB must be a non-negative integer, but found \"-1\"",
+		},
+		"invalidMaxBackups": {
+			env:    map[string]string{logMaxBackupsFlag: "many"},
+			errMsg: "AWS_SIGNER_NOTATION_PLUGIN_LOG_MAX_BACKUPS must be a non-negative integer, but found \"many\"",
+		},
+		"invalidRedaction": {
+			env:    map[string]string{logRedactionFlag: "off"},
+			errMsg: "AWS_SIGNER_NOTATION_PLUGIN_LOG_REDACTION must be either true or false, but found \"off\"",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			for _, key := range []string{logPathFlag, logLevelFlag, logMaxSizeFlag, logMaxBackupsFlag, logRedactionFlag} {
+				t.Setenv(key, tc.env[key])
+			}
+			opts, err := getLoggerOptions()
+			if tc.errMsg != "" {
+				assert.EqualError(t, err, tc.errMsg, "error message mismatch")
+				return
+			}
+			assert.NoError(t, err, "unexpected error")
+			assert.NotEmpty(t, opts, "logger options not found")
+		})
+	}
+}
+
+type jwsEnvelope struct {
+	header  map[string]interface{}
+	payload []byte
+	certs   []*x509.Certificate
+}
+
+// parseJws parses the JWS envelope and verifies its signature using the signing certificate.
+func parseJws(t *testing.T, envelope []byte) *jwsEnvelope {
+	t.Helper()
+	var jws struct {
+		Payload   string `json:"payload"`
+		Protected string `json:"protected"`
+		Header    struct {
+			X5c [][]byte `json:"x5c"`
+		} `json:"header"`
+		Signature string `json:"signature"`
+	}
+	if err := json.Unmarshal(envelope, &jws); err != nil {
+		t.Fatalf("malformed JWS envelope: %v", err)
+	}
+	env := &jwsEnvelope{}
+	protected, err := base64.RawURLEncoding.DecodeString(jws.Protected)
+	if err != nil {
+		t.Fatalf("malformed JWS protected header: %v", err)
+	}
+	if err := json.Unmarshal(protected, &env.header); err != nil {
+		t.Fatalf("malformed JWS protected header: %v", err)
+	}
+	if env.payload, err = base64.RawURLEncoding.DecodeString(jws.Payload); err != nil {
+		t.Fatalf("malformed JWS payload: %v", err)
+	}
+	for _, der := range jws.Header.X5c {
+		cert, err := x509.ParseCertificate(der)
+		if err != nil {
+			t.Fatalf("malformed certificate in JWS envelope: %v", err)
+		}
+		env.certs = append(env.certs, cert)
+	}
+	if len(env.certs) < 2 {
+		t.Fatalf("expected certificate chain in JWS envelope, found %d certificates", len(env.certs))
+	}
+
+	assert.Equal(t, "ES384", env.header["alg"], "alg mismatch")
+	sig, err := base64.RawURLEncoding.DecodeString(jws.Signature)
+	if err != nil || len(sig) != 96 {
+		t.Fatalf("malformed JWS signature")
+	}
+	digest := sha512.Sum384([]byte(jws.Protected + "." + jws.Payload))
+	r, s := new(big.Int).SetBytes(sig[:48]), new(big.Int).SetBytes(sig[48:])
+	assert.True(t, ecdsa.Verify(env.certs[0].PublicKey.(*ecdsa.PublicKey), digest[:], r, s), "JWS signature verification failed")
+	return env
+}
+
+func newFakeSigner(t *testing.T) *fakesigner.Server {
+	t.Helper()
+	server, err := fakesigner.NewServer()
+	if err != nil {
+		t.Fatalf("failed to start fake AWS Signer: %v", err)
+	}
+	t.Cleanup(server.Close)
+	return server
+}
+
+func getPluginConfig(server *fakesigner.Server) map[string]string {
+	return map[string]string{
+		"aws-region":              fakesigner.Region,
+		"aws-signer-endpoint-url": server.URL,
+	}
+}
+
+func getGenerateEnvelopeRequest(server *fakesigner.Server, keyID string) *plugin.GenerateEnvelopeRequest {
+	return &plugin.GenerateEnvelopeRequest{
+		ContractVersion:       plugin.ContractVersion,
+		KeyID:                 keyID,
+		PayloadType:           "application/vnd.oci.descriptor.v1+json",
+		SignatureEnvelopeType: "application/jose+json",
+		Payload:               []byte(testDescriptor),
+		PluginConfig:          getPluginConfig(server),
+	}
+}
+
+func getVerifySignatureRequest(server *fakesigner.Server, env *jwsEnvelope, trustedIdentity string) *plugin.VerifySignatureRequest {
+	signingTime, _ := time.Parse(time.RFC3339, env.header["io.cncf.notary.authenticSigningTime"].(string))
+	expiry, _ := time.Parse(time.RFC3339, env.header["io.cncf.notary.expiry"].(string))
+	var certChain [][]byte
+	for _, cert := range env.certs {
+		certChain = append(certChain, cert.Raw)
+	}
+	return &plugin.VerifySignatureRequest{
+		ContractVersion: plugin.ContractVersion,
+		Signature: plugin.Signature{
+			CriticalAttributes: plugin.CriticalAttributes{
+				ContentType:          env.header["cty"].(string),
+				SigningScheme:        env.header["io.cncf.notary.signingScheme"].(string),
+				Expiry:               &expiry,
+				AuthenticSigningTime: &signingTime,
+				ExtendedAttributes: map[string]interface{}{
+					"com.amazonaws.signer.signingProfileVersion": env.header["com.amazonaws.signer.signingProfileVersion"],
+					"com.amazonaws.signer.signingJob":            env.header["com.amazonaws.signer.signingJob"],
+				},
+			},
+			UnprocessedAttributes: []string{"com.amazonaws.signer.signingProfileVersion", "com.amazonaws.signer.signingJob"},
+			CertificateChain:      certChain,
+		},
+		TrustPolicy: plugin.TrustPolicy{
+			TrustedIdentities:     []string{trustedIdentity},
+			SignatureVerification: []plugin.Capability{plugin.CapabilityTrustedIdentityVerifier, plugin.CapabilityRevocationCheckVerifier},
+		},
+		PluginConfig: getPluginConfig(server),
+	}
+}
+
+// runPlugin executes the plugin command the same way notation does, i.e. by passing the request through stdin and
+// reading the response from stdout, or the error from stderr.
+func runPlugin(t *testing.T, command plugin.Command, req, res interface{}, env ...string) error {
+	t.Helper()
+	input, err := json.Marshal(req)
+	if err != nil {
+		t.Fatalf("failed to marshal request: %v", err)
+	}
+
+	home := t.TempDir()
+	cmd := exec.Command(os.Args[0], string(command))
+	cmd.Env = append(os.Environ(),
+		e2eFlag+"=true",
+		"HOME="+home,
+		"AWS_CONFIG_FILE="+filepath.Join(home, "config"),
+		"AWS_SHARED_CREDENTIALS_FILE="+filepath.Join(home, "credentials"),
+		"AWS_ACCESS_KEY_ID=AKIDEXAMPLE",
+		"AWS_SECRET_ACCESS_KEY=secret",
+		"AWS_EC2_METADATA_DISABLED=true",
+		"XDG_CONFIG_HOME="+filepath.Join(home, ".config"),
+	)
+	cmd.Env = append(cmd.Env, env...)
+	cmd.Stdin = bytes.NewReader(input)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) {
+			t.Fatalf("failed to run plugin: %v", err)
+		}
+		var plgErr plugin.Error
+		if jsonErr := json.Unmarshal(stderr.Bytes(), &plgErr); jsonErr != nil {
+			t.Fatalf("plugin failed with unexpected output %q: %v", strings.TrimSpace(stderr.String()), err)
+		}
+		return &plgErr
+	}
+	if err := json.Unmarshal(stdout.Bytes(), res); err != nil {
+		t.Fatalf("plugin returned malformed response %q: %v", stdout.String(), err)
+	}
+	return nil
+}
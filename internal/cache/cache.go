@@ -0,0 +1,179 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// Package cache provides a file system based cache for AWS Signer revocation status which can be shared by
+// concurrently running plugin processes.
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// pruneInterval is the minimum interval between sweeps of expired entries, which are otherwise only removed when
+// they are read again.
+const pruneInterval = time.Hour
+
+// pruneMarker is the file whose modification time records the last sweep of expired entries.
+const pruneMarker = ".pruned"
+
+var userConfigDir = os.UserConfigDir // for unit test
+
+// RevocationEntry is a cached GetRevocationStatus result.
+type RevocationEntry struct {
+	RevokedEntities []string  `json:"revokedEntities"`
+	ExpiresAt       time.Time `json:"expiresAt"`
+}
+
+// Revocation caches revocation status on disk. Results with revoked entities are cached for ttl and results
+// without revoked entities (negative results) are cached for negativeTTL.
+type Revocation struct {
+	dir         string
+	ttl         time.Duration
+	negativeTTL time.Duration
+	now         func() time.Time
+}
+
+// NewRevocation creates a new Revocation cache stored in user config directory.
+func NewRevocation(ttl, negativeTTL time.Duration) (*Revocation, error) {
+	cfgDir, err := userConfigDir()
+	if err != nil {
+		return nil, err
+	}
+
+	dir := filepath.Join(cfgDir, "notation-aws-signer", "cache", "revocation")
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+
+	return &Revocation{
+		dir:         dir,
+		ttl:         ttl,
+		negativeTTL: negativeTTL,
+		now:         time.Now,
+	}, nil
+}
+
+// RevocationKey returns the cache key for given GetRevocationStatus inputs.
+func RevocationKey(profileVersionArn, jobArn string, certHashes []string, signingTime time.Time) string {
+	h := sha256.New()
+	h.Write([]byte(strings.Join([]string{profileVersionArn, jobArn, strings.Join(certHashes, ","), signingTime.UTC().Format(time.RFC3339Nano)}, "\n")))
+	return fmt.Sprintf("%x", h.Sum(nil))
+}
+
+// Get returns the revoked entities cached for key. The second return value is false if entry is not present,
+// unreadable or expired.
+func (c *Revocation) Get(key string) ([]string, bool) {
+	b, err := os.ReadFile(c.path(key))
+	if err != nil {
+		return nil, false
+	}
+
+	var entry RevocationEntry
+	if err := json.Unmarshal(b, &entry); err != nil {
+		return nil, false
+	}
+	if !c.now().Before(entry.ExpiresAt) {
+		_ = os.Remove(c.path(key))
+		return nil, false
+	}
+	return entry.RevokedEntities, true
+}
+
+// Set caches revokedEntities for key. The entry is written to a temporary file and renamed so that concurrent
+// readers never observe a partially written entry. Expired entries are removed at most once per pruneInterval.
+func (c *Revocation) Set(key string, revokedEntities []string) error {
+	c.pruneIfDue()
+
+	ttl := c.ttl
+	if len(revokedEntities) == 0 {
+		ttl = c.negativeTTL
+	}
+	if ttl <= 0 {
+		return nil
+	}
+
+	b, err := json.Marshal(RevocationEntry{
+		RevokedEntities: revokedEntities,
+		ExpiresAt:       c.now().Add(ttl),
+	})
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(c.dir, key+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), c.path(key))
+}
+
+// pruneIfDue removes expired and unreadable entries, as well as temporary files left behind by interrupted
+// processes, if the last sweep happened more than pruneInterval ago. The marker is updated before sweeping, so
+// concurrently running plugin processes rarely sweep at the same time. Failure to sweep is ignored.
+func (c *Revocation) pruneIfDue() {
+	now := c.now()
+	marker := filepath.Join(c.dir, pruneMarker)
+	if info, err := os.Stat(marker); err == nil && now.Sub(info.ModTime()) < pruneInterval {
+		return
+	}
+	if err := os.WriteFile(marker, nil, 0600); err != nil {
+		return
+	}
+	_ = os.Chtimes(marker, now, now)
+	c.prune(now)
+}
+
+func (c *Revocation) prune(now time.Time) {
+	dirEntries, err := os.ReadDir(c.dir)
+	if err != nil {
+		return
+	}
+	for _, dirEntry := range dirEntries {
+		name := dirEntry.Name()
+		path := filepath.Join(c.dir, name)
+		switch {
+		case strings.HasSuffix(name, ".tmp"):
+			if info, err := dirEntry.Info(); err == nil && now.Sub(info.ModTime()) > pruneInterval {
+				_ = os.Remove(path)
+			}
+		case strings.HasSuffix(name, ".json"):
+			b, err := os.ReadFile(path)
+			if err != nil {
+				continue
+			}
+			var entry RevocationEntry
+			if err := json.Unmarshal(b, &entry); err != nil || !now.Before(entry.ExpiresAt) {
+				_ = os.Remove(path)
+			}
+		}
+	}
+}
+
+func (c *Revocation) path(key string) string {
+	return filepath.Join(c.dir, key+".json")
+}
@@ -0,0 +1,153 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package cache
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testKey = "testKey"
+
+func TestRevocation(t *testing.T) {
+	c := setupTestCache(t, time.Hour, time.Minute)
+	_, ok := c.Get(testKey)
+	assert.False(t, ok, "expected cache miss")
+
+	revoked := []string{"arn:aws:signer:us-west-2:000000000000:/signing-jobs/1"}
+	assert.NoError(t, c.Set(testKey, revoked))
+	actual, ok := c.Get(testKey)
+	assert.True(t, ok, "expected cache hit")
+	assert.Equal(t, revoked, actual)
+
+	assert.NoError(t, c.Set(testKey, []string{}))
+	actual, ok = c.Get(testKey)
+	assert.True(t, ok, "expected cache hit")
+	assert.Empty(t, actual)
+}
+
+func TestRevocation_Expiry(t *testing.T) {
+	c := setupTestCache(t, time.Hour, time.Minute)
+	now := time.Now()
+	c.now = func() time.Time { return now }
+
+	assert.NoError(t, c.Set("revoked", []string{"revokedEntity"}))
+	assert.NoError(t, c.Set("notRevoked", nil))
+
+	c.now = func() time.Time { return now.Add(2 * time.Minute) }
+	_, ok := c.Get("revoked")
+	assert.True(t, ok, "revoked entry expired before ttl")
+	_, ok = c.Get("notRevoked")
+	assert.False(t, ok, "negative entry not expired after negative ttl")
+
+	c.now = func() time.Time { return now.Add(2 * time.Hour) }
+	_, ok = c.Get("revoked")
+	assert.False(t, ok, "revoked entry not expired after ttl")
+}
+
+func TestRevocation_Prune(t *testing.T) {
+	c := setupTestCache(t, time.Hour, time.Minute)
+	now := time.Now()
+	c.now = func() time.Time { return now }
+
+	assert.NoError(t, c.Set("revoked", []string{"revokedEntity"}))
+	assert.NoError(t, c.Set("notRevoked", nil))
+	assert.NoError(t, os.WriteFile(c.path("corrupt"), []byte("{corrupt"), 0600))
+	staleTmp := filepath.Join(c.dir, "interrupted.123.tmp")
+	assert.NoError(t, os.WriteFile(staleTmp, nil, 0600))
+	_ = os.Chtimes(staleTmp, now, now)
+
+	// expired negative entry is retained until the next sweep is due
+	c.now = func() time.Time { return now.Add(2 * time.Minute) }
+	assert.NoError(t, c.Set("recent", nil))
+	assert.FileExists(t, c.path("notRevoked"), "entry pruned before prune interval")
+
+	c.now = func() time.Time { return now.Add(90 * time.Minute) }
+	assert.NoError(t, c.Set("latest", []string{"revokedEntity"}))
+	for _, key := range []string{"revoked", "notRevoked", "recent", "corrupt"} {
+		assert.NoFileExists(t, c.path(key), "expired or corrupt entry not pruned")
+	}
+	assert.NoFileExists(t, staleTmp, "stale temporary file not pruned")
+	_, ok := c.Get("latest")
+	assert.True(t, ok, "expected cache hit for unexpired entry")
+}
+
+func TestRevocation_ZeroTTL(t *testing.T) {
+	c := setupTestCache(t, time.Hour, 0)
+	assert.NoError(t, c.Set(testKey, nil))
+	_, ok := c.Get(testKey)
+	assert.False(t, ok, "entry cached with zero ttl")
+}
+
+func TestRevocation_CorruptEntry(t *testing.T) {
+	c := setupTestCache(t, time.Hour, time.Hour)
+	assert.NoError(t, os.WriteFile(c.path(testKey), []byte("{corrupt"), 0600))
+	_, ok := c.Get(testKey)
+	assert.False(t, ok, "expected cache miss for corrupt entry")
+}
+
+func TestRevocation_Concurrent(t *testing.T) {
+	c := setupTestCache(t, time.Hour, time.Hour)
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			entities := []string{fmt.Sprintf("entity%d", i)}
+			assert.NoError(t, c.Set(testKey, entities))
+			actual, ok := c.Get(testKey)
+			assert.True(t, ok, "expected cache hit")
+			assert.Len(t, actual, 1)
+		}(i)
+	}
+	wg.Wait()
+
+	tmpFiles, _ := filepath.Glob(filepath.Join(c.dir, "*.tmp"))
+	assert.Empty(t, tmpFiles, "temporary files not cleaned up")
+}
+
+func TestNewRevocation_Error(t *testing.T) {
+	userConfigDir = func() (string, error) {
+		return "", fmt.Errorf("expected error thrown")
+	}
+	_, err := NewRevocation(time.Hour, time.Hour)
+	assert.Error(t, err, "expected error not found")
+}
+
+func TestRevocationKey(t *testing.T) {
+	signingTime := time.Now()
+	key := RevocationKey("profileVersion", "job", []string{"hash1", "hash2"}, signingTime)
+	assert.Equal(t, key, RevocationKey("profileVersion", "job", []string{"hash1", "hash2"}, signingTime))
+	assert.NotEqual(t, key, RevocationKey("profileVersion", "job", []string{"hash1"}, signingTime))
+	assert.NotEqual(t, key, RevocationKey("profileVersion", "job2", []string{"hash1", "hash2"}, signingTime))
+	assert.NotEqual(t, key, RevocationKey("profileVersion", "job", []string{"hash1", "hash2"}, signingTime.Add(time.Second)))
+}
+
+func setupTestCache(t *testing.T, ttl, negativeTTL time.Duration) *Revocation {
+	tempDir := t.TempDir()
+	userConfigDir = func() (string, error) {
+		return tempDir, nil
+	}
+	c, err := NewRevocation(ttl, negativeTTL)
+	if err != nil {
+		t.Fatalf("NewRevocation() returned error: %v", err)
+	}
+	return c
+}
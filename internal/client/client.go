@@ -15,15 +15,29 @@
 package client
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/aws/aws-signer-notation-plugin/internal/logger"
+	"github.com/aws/aws-signer-notation-plugin/internal/partition"
 	"github.com/aws/aws-signer-notation-plugin/internal/version"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/signer"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"github.com/aws/smithy-go"
 	"github.com/aws/smithy-go/logging"
 	"github.com/aws/smithy-go/middleware"
 	"github.com/notaryproject/notation-plugin-framework-go/plugin"
@@ -35,36 +49,126 @@ const (
 	configKeyAwsProfile     = "aws-profile"
 	configKeyAwsRegion      = "aws-region"
 	configKeySignerEndpoint = "aws-signer-endpoint-url"
+	configKeyStsEndpoint    = "aws-sts-endpoint-url"
+	configKeyMaxAttempts    = "aws-max-attempts"
+	configKeyMaxBackoff     = "aws-max-backoff"
+	configKeyRetryMode      = "aws-retry-mode"
+
+	errMsgInvalidConfigFmt = "invalid value %q for plugin config %q, %s."
 )
 
-// NewAWSSigner creates new AWS Signer client from given pluginConfig
-func NewAWSSigner(ctx context.Context, pluginConfig map[string]string) (*signer.Client, error) {
+// clientConfigKeys are the plugin config keys used to create the AWS Signer client.
+var clientConfigKeys = []string{
+	configKeyAwsProfile,
+	configKeyAwsRegion,
+	configKeySignerEndpoint,
+	configKeyStsEndpoint,
+	configKeyMaxAttempts,
+	configKeyMaxBackoff,
+	configKeyRetryMode,
+	configKeyRoleArn,
+	configKeyRoleExternalID,
+	configKeyRoleSessionName,
+	configKeyRoleDuration,
+	configKeyRoleChain,
+	configKeyWebIdentityTokenFile}
+
+// ConfigKey returns the key identifying the AWS Signer client created by NewAWSSigner from given pluginConfig, i.e.
+// plugin configs with the same key create equivalent clients.
+func ConfigKey(pluginConfig map[string]string) string {
+	var b strings.Builder
+	for _, key := range clientConfigKeys {
+		if val, ok := pluginConfig[key]; ok {
+			fmt.Fprintf(&b, "%s=%q\n", key, val)
+		}
+	}
+	return b.String()
+}
+
+// NewAWSSigner creates new AWS Signer client from given pluginConfig. The optional optFns are applied after the
+// load options derived from pluginConfig.
+func NewAWSSigner(ctx context.Context, pluginConfig map[string]string, optFns ...func(*config.LoadOptions) error) (*signer.Client, error) {
 	log := logger.GetLogger(ctx)
 	log.Debugln("Initializing Signer Client")
-	loadOptions := getLoadOptions(ctx, pluginConfig)
+	cfg, err := LoadConfig(ctx, pluginConfig, optFns...)
+	if err != nil {
+		return nil, err
+	}
+	s := signer.NewFromConfig(cfg)
+
+	log.Debugln("Initialized Signer Client")
+	return s, nil
+}
+
+// LoadConfig returns the AWS config, including credentials of the assumed roles if any, used to create AWS service
+// clients from given pluginConfig. The optional optFns are applied after the load options derived from pluginConfig.
+func LoadConfig(ctx context.Context, pluginConfig map[string]string, optFns ...func(*config.LoadOptions) error) (aws.Config, error) {
+	loadOptions, err := getLoadOptions(ctx, pluginConfig)
+	if err != nil {
+		return aws.Config{}, err
+	}
+	loadOptions = append(loadOptions, optFns...)
+	roleConfig, err := getAssumeRoleConfig(pluginConfig)
+	if err != nil {
+		return aws.Config{}, err
+	}
 
 	// Use default config for aws credentials
 	defaultConfig, err := config.LoadDefaultConfig(ctx, loadOptions...)
 	if err != nil {
-		return nil, plugin.NewGenericError(err.Error())
+		return aws.Config{}, plugin.NewGenericError(err.Error())
+	}
+	if defaultConfig.HTTPClient == nil {
+		defaultConfig.HTTPClient = awshttp.NewBuildableClient()
+	}
+	defaultConfig.HTTPClient = bufferedBodyClient{client: defaultConfig.HTTPClient}
+	if roleConfig != nil {
+		roleConfig.assumeRoles(ctx, &defaultConfig)
 	}
-	s, err := signer.NewFromConfig(defaultConfig), nil
+	return defaultConfig, nil
+}
 
-	log.Debugln("Initialized Signer Client")
-	return s, err
+// bufferedBodyClient sends requests with their body buffered in memory. The AWS SDK closes the request body as soon as
+// the response arrives, and closing it while the HTTP transport is still checking the body for trailing data makes
+// the transport fail the request write and drop the connection before the response body is read.
+type bufferedBodyClient struct {
+	client aws.HTTPClient
 }
 
-func getLoadOptions(ctx context.Context, pluginConfig map[string]string) []func(*config.LoadOptions) error {
+// Do buffers the body of req, if any, and sends req using the wrapped client.
+func (c bufferedBodyClient) Do(req *http.Request) (*http.Response, error) {
+	if req.Body != nil && req.Body != http.NoBody {
+		body, err := io.ReadAll(req.Body)
+		if err != nil {
+			return nil, err
+		}
+		req.Body = io.NopCloser(bytes.NewReader(body))
+		req.GetBody = func() (io.ReadCloser, error) {
+			return io.NopCloser(bytes.NewReader(body)), nil
+		}
+	}
+	return c.client.Do(req)
+}
+
+func getLoadOptions(ctx context.Context, pluginConfig map[string]string) ([]func(*config.LoadOptions) error, error) {
 	log := logger.GetLogger(ctx)
 	var loadOptions []func(*config.LoadOptions) error
-	if customEndpoint, ok := pluginConfig[configKeySignerEndpoint]; ok {
+	customEndpoints := map[string]string{}
+	if customEndpoint, ok := pluginConfig[configKeySignerEndpoint]; ok && customEndpoint != "" {
+		customEndpoints[signer.ServiceID] = customEndpoint
+	}
+	if customEndpoint, ok := pluginConfig[configKeyStsEndpoint]; ok && customEndpoint != "" {
+		customEndpoints[sts.ServiceID] = customEndpoint
+	}
+	if len(customEndpoints) > 0 {
 		customResolver := aws.EndpointResolverWithOptionsFunc(func(service, region string, options ...interface{}) (aws.Endpoint, error) {
-			if service == signer.ServiceID && customEndpoint != "" {
-				log.Debug("AWS Signer endpoint override: " + customEndpoint)
+			if customEndpoint, ok := customEndpoints[service]; ok {
+				log.Debugf("AWS %s endpoint override: %s\n", service, customEndpoint)
 				return aws.Endpoint{
-					PartitionID:   "aws",
-					URL:           customEndpoint,
-					SigningRegion: region,
+					PartitionID:       partition.ForRegion(region),
+					URL:               customEndpoint,
+					SigningRegion:     region,
+					HostnameImmutable: isHostnameImmutable(customEndpoint),
 				}, nil
 			}
 			// returning EndpointNotFoundError will allow the service to fall back to its default resolution
@@ -83,17 +187,122 @@ func getLoadOptions(ctx context.Context, pluginConfig map[string]string) []func(
 		log.Debugf("AWS Signer credential profile: %s\n", credentialProfile)
 	}
 
+	retryer, err := getRetryer(pluginConfig)
+	if err != nil {
+		return nil, err
+	}
+	if retryer != nil {
+		loadOptions = append(loadOptions, config.WithRetryer(retryer))
+		log.Debugf("AWS Signer retry config: mode %s, max attempts %d\n", pluginConfig[configKeyRetryMode], retryer().MaxAttempts())
+	}
+
 	loadOptions = append(loadOptions, config.WithAPIOptions([]func(*middleware.Stack) error{
 		awsmiddleware.AddUserAgentKeyValue("aws-signer-caller", "NotationPlugin/"+version.GetVersion()),
+		addTraceContextPropagation,
 	}))
 
 	if log.IsDebug() {
 		loadOptions = append(loadOptions, config.WithClientLogMode(aws.LogRequestWithBody|aws.LogResponseWithBody))
 		loadOptions = append(loadOptions, config.WithLogConfigurationWarnings(true))
 		loadOptions = append(loadOptions, config.WithLogger(logging.LoggerFunc(func(_ logging.Classification, format string, v ...interface{}) {
-			log.Debugf("AWS call %s\n", fmt.Sprintf(format, v))
+			log.Debugf("AWS call %s\n", fmt.Sprintf(format, v...))
 		})))
 	}
 
-	return loadOptions
+	return loadOptions, nil
+}
+
+// isHostnameImmutable returns true if the endpoint host is an IP address or localhost, since API specific host
+// prefixes, like "verification." used by GetRevocationStatus API, can't be applied to such hosts.
+func isHostnameImmutable(endpoint string) bool {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return false
+	}
+	host := u.Hostname()
+	return host == "localhost" || net.ParseIP(host) != nil
+}
+
+// getRetryer returns the retryer configured through plugin config, or nil if SDK default retryer should be used.
+func getRetryer(pluginConfig map[string]string) (func() aws.Retryer, error) {
+	maxAttemptsVal, hasMaxAttempts := pluginConfig[configKeyMaxAttempts]
+	maxBackoffVal, hasMaxBackoff := pluginConfig[configKeyMaxBackoff]
+	retryModeVal, hasRetryMode := pluginConfig[configKeyRetryMode]
+	if !hasMaxAttempts && !hasMaxBackoff && !hasRetryMode {
+		return nil, nil
+	}
+
+	var maxAttempts int
+	if hasMaxAttempts {
+		var err error
+		if maxAttempts, err = strconv.Atoi(maxAttemptsVal); err != nil || maxAttempts < 1 {
+			return nil, plugin.NewValidationErrorf(errMsgInvalidConfigFmt, maxAttemptsVal, configKeyMaxAttempts, "expected a positive integer")
+		}
+	}
+	var maxBackoff time.Duration
+	if hasMaxBackoff {
+		var err error
+		if maxBackoff, err = time.ParseDuration(maxBackoffVal); err != nil || maxBackoff <= 0 {
+			return nil, plugin.NewValidationErrorf(errMsgInvalidConfigFmt, maxBackoffVal, configKeyMaxBackoff, "expected a positive duration such as \"20s\"")
+		}
+	}
+	retryMode := aws.RetryModeStandard
+	if hasRetryMode {
+		var err error
+		if retryMode, err = aws.ParseRetryMode(retryModeVal); err != nil {
+			return nil, plugin.NewValidationErrorf(errMsgInvalidConfigFmt, retryModeVal, configKeyRetryMode, "supported values are: standard, adaptive")
+		}
+	}
+
+	standardOptions := func(o *retry.StandardOptions) {
+		if maxAttempts > 0 {
+			o.MaxAttempts = maxAttempts
+		}
+		if maxBackoff > 0 {
+			o.MaxBackoff = maxBackoff
+		}
+	}
+	if retryMode == aws.RetryModeAdaptive {
+		return func() aws.Retryer {
+			return retry.NewAdaptiveMode(func(o *retry.AdaptiveModeOptions) {
+				o.StandardOptions = append(o.StandardOptions, standardOptions)
+			})
+		}, nil
+	}
+	return func() aws.Retryer {
+		return retry.NewStandard(standardOptions)
+	}, nil
+}
+
+// RequestID returns the AWS request ID of the failed API call, or empty string if the API call didn't get a response.
+func RequestID(err error) string {
+	var re *awshttp.ResponseError
+	if errors.As(err, &re) {
+		return re.ServiceRequestID()
+	}
+	return ""
+}
+
+// ResultRequestID returns the AWS request ID from the result metadata of the succeeded API call.
+func ResultRequestID(metadata middleware.Metadata) string {
+	requestID, _ := awsmiddleware.GetRequestIDMetadata(metadata)
+	return requestID
+}
+
+// ErrorCode returns the plugin error code the failure of an AWS API call is reported with.
+func ErrorCode(err error) plugin.ErrorCode {
+	var apiError smithy.APIError
+	if !errors.As(err, &apiError) {
+		return plugin.ErrorCodeGeneric
+	}
+	switch apiError.ErrorCode() {
+	case "NotFoundException", "ResourceNotFoundException", "ValidationException", "BadRequestException":
+		return plugin.ErrorCodeValidation
+	case "ThrottlingException":
+		return plugin.ErrorCodeThrottled
+	case "AccessDeniedException":
+		return plugin.ErrorCodeAccessDenied
+	default:
+		return plugin.ErrorCodeGeneric
+	}
 }
@@ -15,10 +15,26 @@ package client
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"strings"
+	"sync/atomic"
 	"testing"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsmiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/signer"
 	"github.com/aws/aws-signer-notation-plugin/internal/logger"
+	"github.com/aws/smithy-go"
+	"github.com/aws/smithy-go/middleware"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+	"github.com/notaryproject/notation-plugin-framework-go/plugin"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -36,6 +52,129 @@ func TestNewAWSSigner(t *testing.T) {
 	}
 }
 
+func TestConfigKey(t *testing.T) {
+	key := ConfigKey(map[string]string{configKeyAwsRegion: "us-west-2", configKeyRoleArn: "arn:aws:iam::123456789012:role/Signer"})
+	assert.Equal(t, key, ConfigKey(map[string]string{configKeyRoleArn: "arn:aws:iam::123456789012:role/Signer", configKeyAwsRegion: "us-west-2", "aws-signer-revocation-failure-mode": "warn"}), "unrelated plugin config changed the key")
+	assert.NotEqual(t, key, ConfigKey(map[string]string{configKeyAwsRegion: "us-east-1", configKeyRoleArn: "arn:aws:iam::123456789012:role/Signer"}), "different region has the same key")
+	assert.NotEqual(t, key, ConfigKey(map[string]string{configKeyAwsRegion: "us-west-2"}), "missing role has the same key")
+	assert.NotEqual(t, ConfigKey(map[string]string{configKeyAwsRegion: ""}), ConfigKey(nil), "empty region has the same key as missing region")
+}
+
+func TestGetLoadOptions_EndpointPartition(t *testing.T) {
+	tests := map[string]string{
+		"us-west-2":     "aws",
+		"cn-north-1":    "aws-cn",
+		"us-gov-west-1": "aws-us-gov",
+	}
+	for region, expectedPartition := range tests {
+		t.Run(region, func(t *testing.T) {
+			var opts config.LoadOptions
+			loadOptions, _ := getLoadOptions(context.TODO(), map[string]string{configKeySignerEndpoint: "https://127.0.0.1:80/some-endpoint"})
+			for _, fn := range loadOptions {
+				_ = fn(&opts)
+			}
+			endpoint, err := opts.EndpointResolverWithOptions.ResolveEndpoint(signer.ServiceID, region)
+			assert.NoError(t, err, "ResolveEndpoint() returned error")
+			assert.Equal(t, expectedPartition, endpoint.PartitionID, "PartitionID mismatch")
+			assert.Equal(t, region, endpoint.SigningRegion, "SigningRegion mismatch")
+		})
+	}
+}
+
+func TestGetLoadOptions_EndpointHostnameImmutable(t *testing.T) {
+	tests := map[string]bool{
+		"https://127.0.0.1:80/some-endpoint":     true,
+		"http://[::1]:8080":                      true,
+		"http://localhost:8080":                  true,
+		"https://signer.us-west-2.amazonaws.com": false,
+	}
+	for endpointURL, expected := range tests {
+		t.Run(endpointURL, func(t *testing.T) {
+			var opts config.LoadOptions
+			loadOptions, _ := getLoadOptions(context.TODO(), map[string]string{configKeySignerEndpoint: endpointURL})
+			for _, fn := range loadOptions {
+				_ = fn(&opts)
+			}
+			endpoint, err := opts.EndpointResolverWithOptions.ResolveEndpoint(signer.ServiceID, "us-west-2")
+			assert.NoError(t, err, "ResolveEndpoint() returned error")
+			assert.Equal(t, expected, endpoint.HostnameImmutable, "HostnameImmutable mismatch")
+		})
+	}
+}
+
+func TestNewAWSSigner_Retry(t *testing.T) {
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKIDEXAMPLE")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+	tests := map[string]struct {
+		config           map[string]string
+		expectedAttempts int32
+	}{
+		"defaultRetry": {
+			config:           map[string]string{configKeyMaxBackoff: "1ms"},
+			expectedAttempts: 3,
+		},
+		"standardMode": {
+			config:           map[string]string{configKeyMaxAttempts: "5", configKeyMaxBackoff: "1ms", configKeyRetryMode: "standard"},
+			expectedAttempts: 5,
+		},
+		"adaptiveMode": {
+			config:           map[string]string{configKeyMaxAttempts: "4", configKeyMaxBackoff: "1ms", configKeyRetryMode: "adaptive"},
+			expectedAttempts: 4,
+		},
+		"noRetry": {
+			config:           map[string]string{configKeyMaxAttempts: "1"},
+			expectedAttempts: 1,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var attempts int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				atomic.AddInt32(&attempts, 1)
+				w.Header().Set("X-Amzn-Errortype", "ThrottlingException")
+				w.WriteHeader(http.StatusTooManyRequests)
+				_, _ = w.Write([]byte(`{"message":"Rate exceeded"}`))
+			}))
+			defer server.Close()
+
+			test.config[configKeySignerEndpoint] = server.URL
+			test.config[configKeyAwsRegion] = "us-west-2"
+			c, err := NewAWSSigner(context.TODO(), test.config)
+			assert.NoError(t, err, "NewAWSSigner returned error")
+
+			_, err = c.SignPayload(context.TODO(), &signer.SignPayloadInput{
+				Payload:       []byte("payload"),
+				PayloadFormat: aws.String("application/vnd.cncf.notary.payload.v1+json"),
+				ProfileName:   aws.String("NotationProfile"),
+			})
+			var apiErr smithy.APIError
+			if assert.ErrorAs(t, err, &apiErr) {
+				assert.Equal(t, "ThrottlingException", apiErr.ErrorCode())
+			}
+			assert.Equal(t, test.expectedAttempts, atomic.LoadInt32(&attempts), "attempts mismatch")
+		})
+	}
+}
+
+func TestNewAWSSigner_InvalidRetryConfig(t *testing.T) {
+	tests := map[string]map[string]string{
+		"maxAttemptsNotNumber": {configKeyMaxAttempts: "many"},
+		"maxAttemptsZero":      {configKeyMaxAttempts: "0"},
+		"maxBackoffInvalid":    {configKeyMaxBackoff: "forever"},
+		"maxBackoffNegative":   {configKeyMaxBackoff: "-1s"},
+		"retryModeInvalid":     {configKeyRetryMode: "aggressive"},
+	}
+	for name, config := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := NewAWSSigner(context.TODO(), config)
+			var plgErr *plugin.Error
+			if assert.ErrorAs(t, err, &plgErr) {
+				assert.Equal(t, plugin.ErrorCodeValidation, plgErr.ErrCode, "error code mismatch")
+			}
+		})
+	}
+}
+
 func TestNewAWSSigner_Debug(t *testing.T) {
 	// we need this because build fleet might not have XDG_CONFIG_HOME set
 	tempDir, _ := os.MkdirTemp("", "tempDir")
@@ -61,3 +200,95 @@ func TestNewAWSSigner_InvalidProfile(t *testing.T) {
 	_, err := NewAWSSigner(ctx, map[string]string{configKeyAwsProfile: "someProfile"})
 	assert.Error(t, err, "NewAWSSigner returned error")
 }
+
+func TestBufferedBodyClient(t *testing.T) {
+	body := &closeTrackingBody{Reader: strings.NewReader(`{"payload":"abc"}`)}
+	req := httptest.NewRequest(http.MethodPost, "https://signer.us-west-2.amazonaws.com/signing-jobs/with-payload", body)
+	var sent *http.Request
+	_, err := bufferedBodyClient{client: httpClientFunc(func(r *http.Request) (*http.Response, error) {
+		sent = r
+		return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+	})}.Do(req)
+	assert.NoError(t, err, "Do() returned error")
+
+	// the AWS SDK closes the original body once the response arrives, which must not affect the sent body
+	assert.NoError(t, body.Close())
+	sentBody, err := io.ReadAll(sent.Body)
+	assert.NoError(t, err, "failed to read sent body")
+	assert.Equal(t, `{"payload":"abc"}`, string(sentBody), "sent body mismatch")
+	retryBody, err := sent.GetBody()
+	assert.NoError(t, err, "GetBody() returned error")
+	retryBytes, _ := io.ReadAll(retryBody)
+	assert.Equal(t, `{"payload":"abc"}`, string(retryBytes), "GetBody() body mismatch")
+}
+
+type httpClientFunc func(*http.Request) (*http.Response, error)
+
+func (f httpClientFunc) Do(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+// closeTrackingBody fails reads after Close, like a request body closed while the HTTP transport still reads it.
+type closeTrackingBody struct {
+	io.Reader
+	closed bool
+}
+
+func (b *closeTrackingBody) Read(p []byte) (int, error) {
+	if b.closed {
+		return 0, errors.New("read on closed body")
+	}
+	return b.Reader.Read(p)
+}
+
+func (b *closeTrackingBody) Close() error {
+	b.closed = true
+	return nil
+}
+
+func TestRequestID(t *testing.T) {
+	responseErr := &awshttp.ResponseError{
+		ResponseError: &smithyhttp.ResponseError{Err: errors.New("throttled")},
+		RequestID:     "request-id",
+	}
+	assert.Equal(t, "request-id", RequestID(fmt.Errorf("operation error: %w", responseErr)), "RequestID mismatch")
+	assert.Equal(t, "", RequestID(errors.New("request send failed")), "RequestID mismatch")
+
+	var metadata middleware.Metadata
+	assert.Equal(t, "", ResultRequestID(metadata), "ResultRequestID mismatch")
+	awsmiddleware.SetRequestIDMetadata(&metadata, "request-id")
+	assert.Equal(t, "request-id", ResultRequestID(metadata), "ResultRequestID mismatch")
+}
+
+func TestErrorCode(t *testing.T) {
+	tests := map[string]struct {
+		err      error
+		expected plugin.ErrorCode
+	}{
+		"ResourceNotFoundException": {
+			err:      &smithy.GenericAPIError{Code: "ResourceNotFoundException"},
+			expected: plugin.ErrorCodeValidation,
+		},
+		"ThrottlingException": {
+			err:      fmt.Errorf("operation error: %w", &smithy.GenericAPIError{Code: "ThrottlingException"}),
+			expected: plugin.ErrorCodeThrottled,
+		},
+		"AccessDeniedException": {
+			err:      &smithy.GenericAPIError{Code: "AccessDeniedException"},
+			expected: plugin.ErrorCodeAccessDenied,
+		},
+		"InternalServerException": {
+			err:      &smithy.GenericAPIError{Code: "InternalServerException"},
+			expected: plugin.ErrorCodeGeneric,
+		},
+		"nonAPIError": {
+			err:      errors.New("request send failed"),
+			expected: plugin.ErrorCodeGeneric,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.expected, ErrorCode(test.err), "ErrorCode mismatch")
+		})
+	}
+}
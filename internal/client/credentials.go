@@ -0,0 +1,140 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package client
+
+import (
+	"context"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"github.com/aws/aws-signer-notation-plugin/internal/logger"
+	"github.com/notaryproject/notation-plugin-framework-go/plugin"
+)
+
+const (
+	configKeyRoleArn         = "aws-role-arn"
+	configKeyRoleExternalID  = "aws-role-external-id"
+	configKeyRoleSessionName = "aws-role-session-name"
+	configKeyRoleDuration    = "aws-role-duration"
+	configKeyRoleChain       = "aws-role-chain"
+
+	configKeyWebIdentityTokenFile = "aws-web-identity-token-file"
+
+	errMsgMissingConfigFmt        = "plugin config %q is required when %q is set."
+	errMsgWebIdentityTokenFileFmt = "unable to read web identity token file %q configured through plugin config %q. Error: %v"
+)
+
+// assumeRoleConfig contains the role chain to be assumed before calling AWS Signer.
+type assumeRoleConfig struct {
+	roleArns             []string
+	externalID           string
+	sessionName          string
+	duration             time.Duration
+	webIdentityTokenFile string
+}
+
+// getAssumeRoleConfig returns the role chain configured through plugin config, or nil if no role is configured.
+// aws-role-arn is assumed first, using the web identity token if aws-web-identity-token-file is set and default
+// credentials otherwise, followed by each role in comma separated aws-role-chain using the credentials of the
+// previously assumed role.
+func getAssumeRoleConfig(pluginConfig map[string]string) (*assumeRoleConfig, error) {
+	roleArn, ok := pluginConfig[configKeyRoleArn]
+	if tokenFile, hasTokenFile := pluginConfig[configKeyWebIdentityTokenFile]; hasTokenFile {
+		if !ok {
+			return nil, plugin.NewGenericErrorf(errMsgMissingConfigFmt, configKeyRoleArn, configKeyWebIdentityTokenFile)
+		}
+		if _, err := os.Stat(tokenFile); err != nil {
+			return nil, plugin.NewGenericErrorf(errMsgWebIdentityTokenFileFmt, tokenFile, configKeyWebIdentityTokenFile, err)
+		}
+	}
+	if !ok {
+		for _, key := range []string{configKeyRoleExternalID, configKeyRoleSessionName, configKeyRoleDuration, configKeyRoleChain} {
+			if _, ok := pluginConfig[key]; ok {
+				return nil, plugin.NewValidationErrorf(errMsgMissingConfigFmt, configKeyRoleArn, key)
+			}
+		}
+		return nil, nil
+	}
+
+	c := &assumeRoleConfig{
+		roleArns:             []string{roleArn},
+		externalID:           pluginConfig[configKeyRoleExternalID],
+		sessionName:          pluginConfig[configKeyRoleSessionName],
+		webIdentityTokenFile: pluginConfig[configKeyWebIdentityTokenFile],
+	}
+	if chain, ok := pluginConfig[configKeyRoleChain]; ok {
+		for _, arn := range strings.Split(chain, ",") {
+			if arn = strings.TrimSpace(arn); arn != "" {
+				c.roleArns = append(c.roleArns, arn)
+			}
+		}
+	}
+	if val, ok := pluginConfig[configKeyRoleDuration]; ok {
+		d, err := time.ParseDuration(val)
+		if err != nil || d <= 0 {
+			return nil, plugin.NewValidationErrorf(errMsgInvalidConfigFmt, val, configKeyRoleDuration, "expected a positive duration such as \"1h\"")
+		}
+		c.duration = d
+	}
+	return c, nil
+}
+
+// assumeRoles replaces the credentials of cfg with the credentials of the last role in the role chain.
+func (c *assumeRoleConfig) assumeRoles(ctx context.Context, cfg *aws.Config) {
+	log := logger.GetLogger(ctx)
+	for i, roleArn := range c.roleArns {
+		if i == 0 && c.webIdentityTokenFile != "" {
+			log.Debugf("AWS Signer assume role with web identity: %s\n", roleArn)
+			provider := stscreds.NewWebIdentityRoleProvider(sts.NewFromConfig(*cfg, withoutBodyLogging), roleArn, stscreds.IdentityTokenFile(c.webIdentityTokenFile), func(o *stscreds.WebIdentityRoleOptions) {
+				if c.sessionName != "" {
+					o.RoleSessionName = c.sessionName
+				}
+				if c.duration > 0 {
+					o.Duration = c.duration
+				}
+			})
+			cfg.Credentials = aws.NewCredentialsCache(provider)
+			continue
+		}
+
+		log.Debugf("AWS Signer assume role: %s\n", roleArn)
+		provider := stscreds.NewAssumeRoleProvider(sts.NewFromConfig(*cfg, withoutBodyLogging), roleArn, func(o *stscreds.AssumeRoleOptions) {
+			if c.externalID != "" {
+				o.ExternalID = aws.String(c.externalID)
+			}
+			if c.sessionName != "" {
+				o.RoleSessionName = c.sessionName
+			}
+			if c.duration > 0 {
+				o.Duration = c.duration
+			}
+		})
+		cfg.Credentials = aws.NewCredentialsCache(provider)
+	}
+}
+
+// withoutBodyLogging keeps the AWS STS requests and responses logged in debug mode, but not their bodies, which carry
+// the web identity token and the credentials of the assumed role.
+func withoutBodyLogging(o *sts.Options) {
+	if o.ClientLogMode.IsRequestWithBody() {
+		o.ClientLogMode = o.ClientLogMode&^aws.LogRequestWithBody | aws.LogRequest
+	}
+	if o.ClientLogMode.IsResponseWithBody() {
+		o.ClientLogMode = o.ClientLogMode&^aws.LogResponseWithBody | aws.LogResponse
+	}
+}
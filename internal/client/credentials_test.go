@@ -0,0 +1,242 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/signer"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"github.com/notaryproject/notation-plugin-framework-go/plugin"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testRoleArn      = "arn:aws:iam::111122223333:role/SigningRole"
+	testChainRoleArn = "arn:aws:iam::444455556666:role/CentralSigningRole"
+)
+
+var accessKeyRegex = regexp.MustCompile(`Credential=([^/]+)/`)
+
+// stsStandIn is a minimal stand-in for AWS STS AssumeRole API.
+type stsStandIn struct {
+	mu       sync.Mutex
+	requests []assumeRoleRequest
+}
+
+type assumeRoleRequest struct {
+	accessKey       string
+	roleArn         string
+	externalID      string
+	roleSessionName string
+	durationSeconds string
+	action          string
+	token           string
+}
+
+func (s *stsStandIn) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	_ = r.ParseForm()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = append(s.requests, assumeRoleRequest{
+		accessKey:       getAccessKey(r),
+		roleArn:         r.Form.Get("RoleArn"),
+		externalID:      r.Form.Get("ExternalId"),
+		roleSessionName: r.Form.Get("RoleSessionName"),
+		durationSeconds: r.Form.Get("DurationSeconds"),
+		action:          r.Form.Get("Action"),
+		token:           r.Form.Get("WebIdentityToken"),
+	})
+	w.Header().Set("Content-Type", "text/xml")
+	action := r.Form.Get("Action")
+	_, _ = fmt.Fprintf(w, `<%[3]sResponse xmlns="https://sts.amazonaws.com/doc/2011-06-15/">
+  <%[3]sResult>
+    <Credentials>
+      <AccessKeyId>ASIAROLE%[1]d</AccessKeyId>
+      <SecretAccessKey>secret</SecretAccessKey>
+      <SessionToken>token</SessionToken>
+      <Expiration>2099-01-01T00:00:00Z</Expiration>
+    </Credentials>
+    <AssumedRoleUser>
+      <Arn>%[2]s/session</Arn>
+      <AssumedRoleId>AROA:session</AssumedRoleId>
+    </AssumedRoleUser>
+  </%[3]sResult>
+  <ResponseMetadata><RequestId>request-id</RequestId></ResponseMetadata>
+</%[3]sResponse>`, len(s.requests), r.Form.Get("RoleArn"), action)
+}
+
+func TestNewAWSSigner_AssumeRole(t *testing.T) {
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	_ = os.WriteFile(tokenFile, []byte("oidc-token"), 0600)
+	tests := map[string]struct {
+		config            map[string]string
+		expectedRequests  []assumeRoleRequest
+		expectedAccessKey string
+	}{
+		"noRole": {
+			config:            map[string]string{},
+			expectedAccessKey: "AKIDEXAMPLE",
+		},
+		"role": {
+			config: map[string]string{
+				configKeyRoleArn:         testRoleArn,
+				configKeyRoleExternalID:  "external-id",
+				configKeyRoleSessionName: "notation",
+				configKeyRoleDuration:    "30m",
+			},
+			expectedRequests: []assumeRoleRequest{
+				{accessKey: "AKIDEXAMPLE", roleArn: testRoleArn, externalID: "external-id", roleSessionName: "notation", durationSeconds: "1800", action: "AssumeRole"},
+			},
+			expectedAccessKey: "ASIAROLE1",
+		},
+		"webIdentity": {
+			config: map[string]string{
+				configKeyRoleArn:              testRoleArn,
+				configKeyRoleSessionName:      "notation",
+				configKeyWebIdentityTokenFile: tokenFile,
+			},
+			expectedRequests: []assumeRoleRequest{
+				{roleArn: testRoleArn, roleSessionName: "notation", action: "AssumeRoleWithWebIdentity", token: "oidc-token"},
+			},
+			expectedAccessKey: "ASIAROLE1",
+		},
+		"webIdentityRoleChain": {
+			config: map[string]string{
+				configKeyRoleArn:              testRoleArn,
+				configKeyRoleSessionName:      "notation",
+				configKeyRoleChain:            testChainRoleArn,
+				configKeyWebIdentityTokenFile: tokenFile,
+			},
+			expectedRequests: []assumeRoleRequest{
+				{roleArn: testRoleArn, roleSessionName: "notation", action: "AssumeRoleWithWebIdentity", token: "oidc-token"},
+				{accessKey: "ASIAROLE1", roleArn: testChainRoleArn, roleSessionName: "notation", durationSeconds: "900", action: "AssumeRole"},
+			},
+			expectedAccessKey: "ASIAROLE2",
+		},
+		"roleChain": {
+			config: map[string]string{
+				configKeyRoleArn:         testRoleArn,
+				configKeyRoleSessionName: "notation",
+				configKeyRoleChain:       testChainRoleArn + ", " + testRoleArn,
+			},
+			expectedRequests: []assumeRoleRequest{
+				{accessKey: "AKIDEXAMPLE", roleArn: testRoleArn, roleSessionName: "notation", durationSeconds: "900", action: "AssumeRole"},
+				{accessKey: "ASIAROLE1", roleArn: testChainRoleArn, roleSessionName: "notation", durationSeconds: "900", action: "AssumeRole"},
+				{accessKey: "ASIAROLE2", roleArn: testRoleArn, roleSessionName: "notation", durationSeconds: "900", action: "AssumeRole"},
+			},
+			expectedAccessKey: "ASIAROLE3",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv("AWS_ACCESS_KEY_ID", "AKIDEXAMPLE")
+			t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+			stsServer := &stsStandIn{}
+			stsEndpoint := httptest.NewServer(stsServer)
+			defer stsEndpoint.Close()
+			var signerAccessKey string
+			signerEndpoint := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				signerAccessKey = getAccessKey(r)
+				_, _ = w.Write([]byte(`{"jobId":"1","signature":"c2lnbmF0dXJl"}`))
+			}))
+			defer signerEndpoint.Close()
+
+			test.config[configKeyAwsRegion] = "us-west-2"
+			test.config[configKeyStsEndpoint] = stsEndpoint.URL
+			test.config[configKeySignerEndpoint] = signerEndpoint.URL
+			c, err := NewAWSSigner(context.TODO(), test.config)
+			if err != nil {
+				t.Fatalf("NewAWSSigner returned error: %v", err)
+			}
+			_, err = c.SignPayload(context.TODO(), &signer.SignPayloadInput{
+				Payload:       []byte("payload"),
+				PayloadFormat: aws.String("application/vnd.cncf.notary.payload.v1+json"),
+				ProfileName:   aws.String("NotationProfile"),
+			})
+			assert.NoError(t, err, "SignPayload returned error")
+			assert.Equal(t, test.expectedRequests, stsServer.requests, "AssumeRole requests mismatch")
+			assert.Equal(t, test.expectedAccessKey, signerAccessKey, "SignPayload credentials mismatch")
+		})
+	}
+}
+
+func TestNewAWSSigner_InvalidAssumeRoleConfig(t *testing.T) {
+	tests := map[string]map[string]string{
+		"missingRoleArnForExternalID": {configKeyRoleExternalID: "id"},
+		"missingRoleArnForChain":      {configKeyRoleChain: testChainRoleArn},
+		"invalidDuration":             {configKeyRoleArn: testRoleArn, configKeyRoleDuration: "1 hour"},
+	}
+	for name, config := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := NewAWSSigner(context.TODO(), config)
+			var plgErr *plugin.Error
+			if assert.ErrorAs(t, err, &plgErr) {
+				assert.Equal(t, plugin.ErrorCodeValidation, plgErr.ErrCode, "error code mismatch")
+			}
+		})
+	}
+}
+
+func TestNewAWSSigner_InvalidWebIdentityConfig(t *testing.T) {
+	tests := map[string]struct {
+		config   map[string]string
+		errorMsg string
+	}{
+		"missingRoleArn": {
+			config:   map[string]string{configKeyWebIdentityTokenFile: "/token"},
+			errorMsg: "plugin config \"aws-role-arn\" is required when \"aws-web-identity-token-file\" is set.",
+		},
+		"missingTokenFile": {
+			config:   map[string]string{configKeyWebIdentityTokenFile: "/missing/token", configKeyRoleArn: testRoleArn},
+			errorMsg: "unable to read web identity token file \"/missing/token\" configured through plugin config \"aws-web-identity-token-file\". Error: stat /missing/token: no such file or directory",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := NewAWSSigner(context.TODO(), test.config)
+			var plgErr *plugin.Error
+			if assert.ErrorAs(t, err, &plgErr) {
+				assert.Equal(t, plugin.ErrorCodeGeneric, plgErr.ErrCode, "error code mismatch")
+				assert.Equal(t, test.errorMsg, plgErr.Message, "error message mismatch")
+			}
+		})
+	}
+}
+
+func TestWithoutBodyLogging(t *testing.T) {
+	o := &sts.Options{ClientLogMode: aws.LogRetries | aws.LogRequestWithBody | aws.LogResponseWithBody}
+	withoutBodyLogging(o)
+	assert.Equal(t, aws.LogRetries|aws.LogRequest|aws.LogResponse, o.ClientLogMode, "ClientLogMode mismatch")
+
+	o = &sts.Options{}
+	withoutBodyLogging(o)
+	assert.Equal(t, aws.ClientLogMode(0), o.ClientLogMode, "ClientLogMode mismatch")
+}
+
+func getAccessKey(r *http.Request) string {
+	if m := accessKeyRegex.FindStringSubmatch(r.Header.Get("Authorization")); m != nil {
+		return m[1]
+	}
+	return ""
+}
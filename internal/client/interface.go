@@ -23,4 +23,6 @@ import (
 type Interface interface {
 	SignPayload(ctx context.Context, params *signer.SignPayloadInput, optFns ...func(*signer.Options)) (*signer.SignPayloadOutput, error)
 	GetRevocationStatus(ctx context.Context, params *signer.GetRevocationStatusInput, optFns ...func(*signer.Options)) (*signer.GetRevocationStatusOutput, error)
+	GetSigningProfile(ctx context.Context, params *signer.GetSigningProfileInput, optFns ...func(*signer.Options)) (*signer.GetSigningProfileOutput, error)
+	DescribeSigningJob(ctx context.Context, params *signer.DescribeSigningJobInput, optFns ...func(*signer.Options)) (*signer.DescribeSigningJobOutput, error)
 }
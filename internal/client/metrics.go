@@ -0,0 +1,70 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package client
+
+import (
+	"context"
+	"time"
+
+	"github.com/aws/aws-signer-notation-plugin/metrics"
+
+	"github.com/aws/aws-sdk-go-v2/service/signer"
+)
+
+// instrumentedClient records the latency and the outcome of the AWS Signer API calls made through the wrapped client.
+type instrumentedClient struct {
+	Interface
+	recorder metrics.Recorder
+}
+
+// WithMetrics returns a client which records the AWS Signer API calls made through c using recorder.
+func WithMetrics(c Interface, recorder metrics.Recorder) Interface {
+	return &instrumentedClient{Interface: c, recorder: recorder}
+}
+
+func (c *instrumentedClient) SignPayload(ctx context.Context, params *signer.SignPayloadInput, optFns ...func(*signer.Options)) (*signer.SignPayloadOutput, error) {
+	start := time.Now()
+	output, err := c.Interface.SignPayload(ctx, params, optFns...)
+	c.observe(metrics.OperationSignPayload, start, err)
+	return output, err
+}
+
+func (c *instrumentedClient) GetRevocationStatus(ctx context.Context, params *signer.GetRevocationStatusInput, optFns ...func(*signer.Options)) (*signer.GetRevocationStatusOutput, error) {
+	start := time.Now()
+	output, err := c.Interface.GetRevocationStatus(ctx, params, optFns...)
+	c.observe(metrics.OperationGetRevocationStatus, start, err)
+	return output, err
+}
+
+func (c *instrumentedClient) GetSigningProfile(ctx context.Context, params *signer.GetSigningProfileInput, optFns ...func(*signer.Options)) (*signer.GetSigningProfileOutput, error) {
+	start := time.Now()
+	output, err := c.Interface.GetSigningProfile(ctx, params, optFns...)
+	c.observe(metrics.OperationGetSigningProfile, start, err)
+	return output, err
+}
+
+func (c *instrumentedClient) DescribeSigningJob(ctx context.Context, params *signer.DescribeSigningJobInput, optFns ...func(*signer.Options)) (*signer.DescribeSigningJobOutput, error) {
+	start := time.Now()
+	output, err := c.Interface.DescribeSigningJob(ctx, params, optFns...)
+	c.observe(metrics.OperationDescribeSigningJob, start, err)
+	return output, err
+}
+
+func (c *instrumentedClient) observe(operation string, start time.Time, err error) {
+	var errorCode string
+	if err != nil {
+		errorCode = string(ErrorCode(err))
+	}
+	c.recorder.ObserveAPICall(operation, time.Since(start), errorCode)
+}
@@ -0,0 +1,37 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package client
+
+import (
+	"context"
+
+	"github.com/aws/smithy-go/middleware"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+const traceContextMiddlewareID = "NotationPluginTraceContext"
+
+// addTraceContextPropagation injects the trace context of the API call into the request headers using the global
+// propagator, so that AWS API calls are correlated with the caller's trace. The global propagator doesn't inject
+// anything unless the caller has set one through otel.SetTextMapPropagator.
+func addTraceContextPropagation(stack *middleware.Stack) error {
+	return stack.Build.Add(middleware.BuildMiddlewareFunc(traceContextMiddlewareID, func(ctx context.Context, in middleware.BuildInput, next middleware.BuildHandler) (middleware.BuildOutput, middleware.Metadata, error) {
+		if req, ok := in.Request.(*smithyhttp.Request); ok {
+			otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+		}
+		return next.HandleBuild(ctx, in)
+	}), middleware.After)
+}
@@ -0,0 +1,75 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/signer"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTraceContextPropagation(t *testing.T) {
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKIDEXAMPLE")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+	spanContext := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{0x4b, 0xf9, 0x2f, 0x35, 0x77, 0xb3, 0x4d, 0xa6, 0xa3, 0xce, 0x92, 0x9d, 0x0e, 0x0e, 0x47, 0x36},
+		SpanID:     trace.SpanID{0x00, 0xf0, 0x67, 0xaa, 0x0b, 0xa9, 0x02, 0xb7},
+		TraceFlags: trace.FlagsSampled,
+	})
+	tests := map[string]struct {
+		propagator  propagation.TextMapPropagator
+		traceparent string
+	}{
+		"noPropagator": {
+			propagator: propagation.NewCompositeTextMapPropagator(),
+		},
+		"traceContextPropagator": {
+			propagator:  propagation.TraceContext{},
+			traceparent: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			otel.SetTextMapPropagator(test.propagator)
+			defer otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator())
+
+			var traceparent string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				traceparent = r.Header.Get("traceparent")
+				_, _ = w.Write([]byte(`{"jobId":"1","signature":"c2lnbmF0dXJl"}`))
+			}))
+			defer server.Close()
+
+			c, err := NewAWSSigner(context.TODO(), map[string]string{configKeySignerEndpoint: server.URL, configKeyAwsRegion: "us-west-2"})
+			assert.NoError(t, err, "NewAWSSigner returned error")
+
+			ctx := trace.ContextWithSpanContext(context.TODO(), spanContext)
+			_, err = c.SignPayload(ctx, &signer.SignPayloadInput{
+				Payload:       []byte("payload"),
+				PayloadFormat: aws.String("application/vnd.cncf.notary.payload.v1+json"),
+				ProfileName:   aws.String("NotationProfile"),
+			})
+			assert.NoError(t, err, "SignPayload returned error")
+			assert.Equal(t, test.traceparent, traceparent, "traceparent header mismatch")
+		})
+	}
+}
@@ -0,0 +1,423 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// Package fakesigner provides a local fake of AWS Signer service for hermetic end-to-end tests. The fake signs
+// payloads using a locally generated ECDSA P-384 certificate chain and serves the revocation status of the entities
+// revoked through Server.Revoke. It can be used with the plugin through aws-signer-endpoint-url plugin config.
+package fakesigner
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha512"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// Region is the AWS region of the signing profiles and signing jobs of the fake.
+	Region = "us-west-2"
+	// AccountID is the AWS account owning the signing profiles and signing jobs of the fake.
+	AccountID = "123456789012"
+	// PlatformID is the signing platform of the signing profiles of the fake.
+	PlatformID = "Notation-OCI-SHA384-ECDSA"
+	// RequestedBy is the principal reported by DescribeSigningJob as the requester of every signing job of the fake.
+	RequestedBy = "arn:aws:iam::123456789012:user/E2ESigner"
+
+	mediaTypeJwsEnvelope      = "application/jose+json"
+	mediaTypeNotationPayload  = "application/vnd.cncf.notary.payload.v1+json"
+	mediaTypeOciDescriptor    = "application/vnd.oci.descriptor.v1+json"
+	signingSchemeAuthority    = "notary.x509.signingAuthority"
+	headerSigningScheme       = "io.cncf.notary.signingScheme"
+	headerSigningTime         = "io.cncf.notary.authenticSigningTime"
+	headerExpiry              = "io.cncf.notary.expiry"
+	headerSigningAgent        = "io.cncf.notary.signingAgent"
+	attrSigningProfileVersion = "com.amazonaws.signer.signingProfileVersion"
+	attrSigningJob            = "com.amazonaws.signer.signingJob"
+	signatureValidityMonths   = 135
+)
+
+// Server is a fake AWS Signer service listening on a local HTTP endpoint.
+type Server struct {
+	// URL of the fake, to be used as aws-signer-endpoint-url plugin config.
+	URL string
+
+	httpServer *httptest.Server
+	certs      []*x509.Certificate
+	key        *ecdsa.PrivateKey
+
+	mu       sync.Mutex
+	profiles map[string]*signingProfile
+	jobs     map[string]*signingProfile
+	revoked  map[string]bool
+	jobCount int
+}
+
+type signingProfile struct {
+	name    string
+	version string
+	status  string
+}
+
+// NewServer starts a fake AWS Signer service with a newly generated certificate chain. The caller must call Close
+// once done.
+func NewServer() (*Server, error) {
+	certs, key, err := newCertificateChain()
+	if err != nil {
+		return nil, err
+	}
+	s := &Server{
+		certs:    certs,
+		key:      key,
+		profiles: map[string]*signingProfile{},
+		jobs:     map[string]*signingProfile{},
+		revoked:  map[string]bool{},
+	}
+	s.httpServer = httptest.NewServer(s)
+	s.URL = s.httpServer.URL
+	return s, nil
+}
+
+// Close shuts down the fake.
+func (s *Server) Close() {
+	s.httpServer.Close()
+}
+
+// Certificates returns the certificate chain used for signing, starting with the signing certificate and ending
+// with the root certificate.
+func (s *Server) Certificates() []*x509.Certificate {
+	return append([]*x509.Certificate{}, s.certs...)
+}
+
+// AddSigningProfile creates an active signing profile with the given name, replacing the existing signing profile
+// with the same name, and returns the signing profile ARN along with the signing profile version ARN.
+func (s *Server) AddSigningProfile(name string) (string, string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p := &signingProfile{name: name, version: newID(10), status: "Active"}
+	s.profiles[name] = p
+	return p.arn(), p.versionArn()
+}
+
+// Revoke marks the given signing profile version ARNs, signing job ARNs or certificate hashes as revoked. Certificate
+// hashes are in the format used by GetRevocationStatus API, see CertificateHash.
+func (s *Server) Revoke(entities ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, e := range entities {
+		s.revoked[e] = true
+	}
+}
+
+// CertificateHash returns the hash of the certificate and its issuer certificate in the format used by
+// GetRevocationStatus API.
+func CertificateHash(cert, issuer *x509.Certificate) string {
+	return hashTBS(cert) + hashTBS(issuer)
+}
+
+func hashTBS(cert *x509.Certificate) string {
+	h := sha512.Sum384(cert.RawTBSCertificate)
+	return hex.EncodeToString(h[:])
+}
+
+// ServeHTTP serves SignPayload, GetSigningProfile, GetRevocationStatus and DescribeSigningJob APIs of AWS Signer.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case r.Method == http.MethodPost && r.URL.Path == "/signing-jobs/with-payload":
+		s.signPayload(w, r)
+	case r.Method == http.MethodGet && r.URL.Path == "/revocations":
+		s.getRevocationStatus(w, r)
+	case r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, "/signing-profiles/"):
+		s.getSigningProfile(w, r)
+	case r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, "/signing-jobs/"):
+		s.describeSigningJob(w, r)
+	default:
+		writeError(w, http.StatusNotFound, "UnknownOperationException", fmt.Sprintf("%s %s is not supported", r.Method, r.URL.Path))
+	}
+}
+
+func (s *Server) signPayload(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		Payload       []byte `json:"payload"`
+		PayloadFormat string `json:"payloadFormat"`
+		ProfileName   string `json:"profileName"`
+		ProfileOwner  string `json:"profileOwner"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		writeError(w, http.StatusBadRequest, "ValidationException", err.Error())
+		return
+	}
+	if input.PayloadFormat != mediaTypeOciDescriptor {
+		writeError(w, http.StatusBadRequest, "ValidationException", fmt.Sprintf("payload format %s is not supported", input.PayloadFormat))
+		return
+	}
+	profile, ok := s.getProfile(input.ProfileName, input.ProfileOwner)
+	if !ok {
+		writeError(w, http.StatusNotFound, "ResourceNotFoundException", fmt.Sprintf("signing profile %s not found", input.ProfileName))
+		return
+	}
+
+	s.mu.Lock()
+	s.jobCount++
+	jobID := fmt.Sprintf("%08d-0000-0000-0000-%s", s.jobCount, newID(12))
+	s.jobs[jobID] = &signingProfile{name: profile.name, version: profile.version, status: profile.status}
+	s.mu.Unlock()
+	jobArn := fmt.Sprintf("arn:aws:signer:%s:%s:/signing-jobs/%s", Region, AccountID, jobID)
+
+	envelope, err := s.signJws(input.Payload, profile.versionArn(), jobArn)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "InternalServiceErrorException", err.Error())
+		return
+	}
+	writeJSON(w, map[string]interface{}{
+		"jobId":     jobID,
+		"jobOwner":  AccountID,
+		"signature": envelope,
+		"metadata": map[string]string{
+			attrSigningProfileVersion: profile.versionArn(),
+			attrSigningJob:            jobArn,
+		},
+	})
+}
+
+func (s *Server) getSigningProfile(w http.ResponseWriter, r *http.Request) {
+	name := strings.TrimPrefix(r.URL.Path, "/signing-profiles/")
+	profile, ok := s.getProfile(name, r.URL.Query().Get("profileOwner"))
+	if !ok {
+		writeError(w, http.StatusNotFound, "ResourceNotFoundException", fmt.Sprintf("signing profile %s not found", name))
+		return
+	}
+	writeJSON(w, map[string]interface{}{
+		"arn":               profile.arn(),
+		"profileName":       profile.name,
+		"profileVersion":    profile.version,
+		"profileVersionArn": profile.versionArn(),
+		"platformId":        PlatformID,
+		"status":            profile.status,
+		"signatureValidityPeriod": map[string]interface{}{
+			"type":  "MONTHS",
+			"value": signatureValidityMonths,
+		},
+	})
+}
+
+func (s *Server) describeSigningJob(w http.ResponseWriter, r *http.Request) {
+	jobID := strings.TrimPrefix(r.URL.Path, "/signing-jobs/")
+	s.mu.Lock()
+	profile, ok := s.jobs[jobID]
+	s.mu.Unlock()
+	if !ok {
+		writeError(w, http.StatusNotFound, "ResourceNotFoundException", fmt.Sprintf("signing job %s not found", jobID))
+		return
+	}
+	writeJSON(w, map[string]interface{}{
+		"jobId":          jobID,
+		"jobOwner":       AccountID,
+		"jobInvoker":     AccountID,
+		"platformId":     PlatformID,
+		"profileName":    profile.name,
+		"profileVersion": profile.version,
+		"requestedBy":    RequestedBy,
+		"status":         "Succeeded",
+	})
+}
+
+func (s *Server) getRevocationStatus(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	for _, key := range []string{"signatureTimestamp", "platformId", "profileVersionArn", "jobArn", "certificateHashes"} {
+		if query.Get(key) == "" {
+			writeError(w, http.StatusBadRequest, "ValidationException", fmt.Sprintf("%s is required", key))
+			return
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	revokedEntities := []string{}
+	for _, entity := range append([]string{query.Get("profileVersionArn"), query.Get("jobArn")}, query["certificateHashes"]...) {
+		if s.revoked[entity] {
+			revokedEntities = append(revokedEntities, entity)
+		}
+	}
+	writeJSON(w, map[string]interface{}{"revokedEntities": revokedEntities})
+}
+
+func (s *Server) getProfile(name, owner string) (*signingProfile, bool) {
+	if owner != "" && owner != AccountID {
+		return nil, false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p, ok := s.profiles[name]
+	return p, ok
+}
+
+// signJws generates a Notary Project JWS signature envelope for the given OCI descriptor.
+func (s *Server) signJws(descriptor []byte, profileVersionArn, jobArn string) ([]byte, error) {
+	payload, err := json.Marshal(map[string]json.RawMessage{"targetArtifact": descriptor})
+	if err != nil {
+		return nil, err
+	}
+	signingTime := time.Now().UTC().Truncate(time.Second)
+	protected, err := json.Marshal(map[string]interface{}{
+		"alg":                     "ES384",
+		"cty":                     mediaTypeNotationPayload,
+		"crit":                    []string{headerSigningScheme, headerSigningTime, headerExpiry, attrSigningProfileVersion, attrSigningJob},
+		headerSigningScheme:       signingSchemeAuthority,
+		headerSigningTime:         signingTime.Format(time.RFC3339),
+		headerExpiry:              signingTime.AddDate(0, signatureValidityMonths, 0).Format(time.RFC3339),
+		attrSigningProfileVersion: profileVersionArn,
+		attrSigningJob:            jobArn,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	encodedProtected := base64.RawURLEncoding.EncodeToString(protected)
+	encodedPayload := base64.RawURLEncoding.EncodeToString(payload)
+	digest := sha512.Sum384([]byte(encodedProtected + "." + encodedPayload))
+	r, sig, err := ecdsa.Sign(rand.Reader, s.key, digest[:])
+	if err != nil {
+		return nil, err
+	}
+	signature := make([]byte, 96)
+	r.FillBytes(signature[:48])
+	sig.FillBytes(signature[48:])
+
+	var x5c [][]byte
+	for _, cert := range s.certs {
+		x5c = append(x5c, cert.Raw)
+	}
+	return json.Marshal(map[string]interface{}{
+		"payload":   encodedPayload,
+		"protected": encodedProtected,
+		"header": map[string]interface{}{
+			"x5c":              x5c,
+			headerSigningAgent: "AWS Signer fake",
+		},
+		"signature": base64.RawURLEncoding.EncodeToString(signature),
+	})
+}
+
+func (p *signingProfile) arn() string {
+	return fmt.Sprintf("arn:aws:signer:%s:%s:/signing-profiles/%s", Region, AccountID, p.name)
+}
+
+func (p *signingProfile) versionArn() string {
+	return p.arn() + "/" + p.version
+}
+
+// newCertificateChain generates a signing certificate, an intermediate CA certificate and a root CA certificate
+// along with the private key of the signing certificate.
+func newCertificateChain() ([]*x509.Certificate, *ecdsa.PrivateKey, error) {
+	now := time.Now()
+	rootTemplate := &x509.Certificate{
+		Subject:               pkix.Name{CommonName: "AWS Signer Fake Root CA", Organization: []string{"AWS Signer Fake"}},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.AddDate(20, 0, 0),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	intermediateTemplate := &x509.Certificate{
+		Subject:               pkix.Name{CommonName: "AWS Signer Fake Intermediate CA", Organization: []string{"AWS Signer Fake"}},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.AddDate(15, 0, 0),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		MaxPathLenZero:        true,
+	}
+	leafTemplate := &x509.Certificate{
+		Subject:     pkix.Name{CommonName: "AWS Signer Fake Signing Certificate", Organization: []string{"AWS Signer Fake"}},
+		NotBefore:   now.Add(-time.Hour),
+		NotAfter:    now.AddDate(12, 0, 0),
+		KeyUsage:    x509.KeyUsageDigitalSignature,
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning},
+	}
+
+	rootCert, rootKey, err := newCertificate(rootTemplate, nil, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	intermediateCert, intermediateKey, err := newCertificate(intermediateTemplate, rootCert, rootKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	leafCert, leafKey, err := newCertificate(leafTemplate, intermediateCert, intermediateKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	return []*x509.Certificate{leafCert, intermediateCert, rootCert}, leafKey, nil
+}
+
+// newCertificate generates a certificate from template signed by the issuer, or a self-signed certificate if issuer
+// is nil.
+func newCertificate(template, issuer *x509.Certificate, issuerKey *ecdsa.PrivateKey) (*x509.Certificate, *ecdsa.PrivateKey, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, nil, err
+	}
+	template.SerialNumber = serial
+	if issuer == nil {
+		issuer, issuerKey = template, key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, issuer, &key.PublicKey, issuerKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, nil, err
+	}
+	return cert, key, nil
+}
+
+func newID(n int) string {
+	b := make([]byte, (n+1)/2)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)[:n]
+}
+
+func newRequestID() string {
+	return fmt.Sprintf("%s-%s-%s-%s-%s", newID(8), newID(4), newID(4), newID(4), newID(12))
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Amzn-Requestid", newRequestID())
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, code, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Amzn-Errortype", code)
+	w.Header().Set("X-Amzn-Requestid", newRequestID())
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]string{"message": message})
+}
@@ -0,0 +1,113 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package fakesigner
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/signer"
+	"github.com/aws/aws-sdk-go-v2/service/signer/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServer_GetSigningProfile(t *testing.T) {
+	server := newTestServer(t)
+	profileArn, profileVersionArn := server.AddSigningProfile("TestProfile")
+
+	output, err := newTestClient(server).GetSigningProfile(context.TODO(), &signer.GetSigningProfileInput{
+		ProfileName:  aws.String("TestProfile"),
+		ProfileOwner: aws.String(AccountID),
+	})
+	if !assert.NoError(t, err, "GetSigningProfile() returned error") {
+		return
+	}
+	assert.Equal(t, profileArn, aws.ToString(output.Arn), "Arn mismatch")
+	assert.Equal(t, profileVersionArn, aws.ToString(output.ProfileVersionArn), "ProfileVersionArn mismatch")
+	assert.Equal(t, PlatformID, aws.ToString(output.PlatformId), "PlatformId mismatch")
+	assert.Equal(t, types.SigningProfileStatusActive, output.Status, "Status mismatch")
+	assert.Equal(t, &types.SignatureValidityPeriod{Type: types.ValidityTypeMonths, Value: signatureValidityMonths}, output.SignatureValidityPeriod, "SignatureValidityPeriod mismatch")
+}
+
+func TestServer_GetSigningProfile_NotFound(t *testing.T) {
+	server := newTestServer(t)
+	_, err := newTestClient(server).GetSigningProfile(context.TODO(), &signer.GetSigningProfileInput{ProfileName: aws.String("Unknown")})
+	var notFound *types.ResourceNotFoundException
+	assert.ErrorAs(t, err, &notFound, "expected ResourceNotFoundException")
+}
+
+func TestServer_DescribeSigningJob(t *testing.T) {
+	server := newTestServer(t)
+	_, profileVersionArn := server.AddSigningProfile("TestProfile")
+	c := newTestClient(server)
+	signOutput, err := c.SignPayload(context.TODO(), &signer.SignPayloadInput{
+		Payload:       []byte(`{"mediaType":"application/vnd.oci.image.manifest.v1+json","digest":"sha256:9834876dcfb05cb167a5c24953eba58c4ac89b1adf57f28f2f9d09af107ee8f0","size":16724}`),
+		PayloadFormat: aws.String(mediaTypeOciDescriptor),
+		ProfileName:   aws.String("TestProfile"),
+	})
+	if !assert.NoError(t, err, "SignPayload() returned error") {
+		return
+	}
+
+	output, err := c.DescribeSigningJob(context.TODO(), &signer.DescribeSigningJobInput{JobId: signOutput.JobId})
+	if !assert.NoError(t, err, "DescribeSigningJob() returned error") {
+		return
+	}
+	assert.Equal(t, "TestProfile", aws.ToString(output.ProfileName), "ProfileName mismatch")
+	assert.Equal(t, profileVersionArn, "arn:aws:signer:"+Region+":"+AccountID+":/signing-profiles/TestProfile/"+aws.ToString(output.ProfileVersion), "ProfileVersion mismatch")
+	assert.Equal(t, RequestedBy, aws.ToString(output.RequestedBy), "RequestedBy mismatch")
+
+	_, err = c.DescribeSigningJob(context.TODO(), &signer.DescribeSigningJobInput{JobId: aws.String("unknown")})
+	var notFound *types.ResourceNotFoundException
+	assert.ErrorAs(t, err, &notFound, "expected ResourceNotFoundException")
+}
+
+func TestServer_UnsupportedOperation(t *testing.T) {
+	server := newTestServer(t)
+	res, err := http.Get(server.URL + "/signing-platforms")
+	if !assert.NoError(t, err, "GET returned error") {
+		return
+	}
+	defer res.Body.Close()
+	assert.Equal(t, http.StatusNotFound, res.StatusCode, "status code mismatch")
+	assert.Equal(t, "UnknownOperationException", res.Header.Get("X-Amzn-Errortype"), "error type mismatch")
+}
+
+func TestCertificateHash(t *testing.T) {
+	server := newTestServer(t)
+	certs := server.Certificates()
+	assert.Len(t, certs, 3, "certificate chain length mismatch")
+	assert.NoError(t, certs[0].CheckSignatureFrom(certs[1]), "signing certificate isn't issued by intermediate certificate")
+	assert.NoError(t, certs[1].CheckSignatureFrom(certs[2]), "intermediate certificate isn't issued by root certificate")
+	assert.Len(t, CertificateHash(certs[0], certs[1]), 192, "certificate hash length mismatch")
+}
+
+func newTestServer(t *testing.T) *Server {
+	server, err := NewServer()
+	if err != nil {
+		t.Fatalf("NewServer() returned error: %v", err)
+	}
+	t.Cleanup(server.Close)
+	return server
+}
+
+func newTestClient(server *Server) *signer.Client {
+	return signer.New(signer.Options{
+		Region:       Region,
+		BaseEndpoint: aws.String(server.URL),
+		Credentials:  aws.AnonymousCredentials{},
+	})
+}
@@ -16,57 +16,195 @@ package logger
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/aws/aws-signer-notation-plugin/internal/version"
+
+	"github.com/notaryproject/notation-plugin-framework-go/log"
 )
 
 type contextKey int
 
 const logContextKey contextKey = iota
 
+// Fields attached to log entries in JSON format using debugLogger.With.
+const (
+	FieldOperation  = "operation"
+	FieldProfileArn = "profileArn"
+	FieldRequestID  = "awsRequestId"
+
+	fieldTimestamp    = "timestamp"
+	fieldLevel        = "level"
+	fieldInvocationID = "invocationId"
+	fieldMessage      = "message"
+)
+
+// Level is the minimum severity of the log entries written by debugLogger.
+type Level int
+
+// Supported log levels, in increasing order of severity.
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+)
+
+const (
+	// DefaultMaxSize is the size in bytes beyond which the log file is rotated, unless configured through
+	// WithRotation.
+	DefaultMaxSize = 10 * 1024 * 1024
+	// DefaultMaxBackups is the number of rotated log files retained, unless configured through WithRotation.
+	DefaultMaxBackups = 5
+)
+
+var levelNames = map[Level]string{
+	LevelDebug: "DEBUG",
+	LevelInfo:  "INFO",
+	LevelWarn:  "WARN",
+	LevelError: "ERROR",
+}
+
 var userConfigDir = os.UserConfigDir // for unit test
 var discardLogger = &debugLogger{}
 
 type debugLogger struct {
-	file *os.File
+	file         *rotatingFile
+	sink         log.Logger
+	path         string
+	level        Level
+	maxSize      int64
+	maxBackups   int
+	json         bool
+	unredacted   bool
+	invocationID string
+	fields       map[string]string
+}
+
+// Option configures the debugLogger created by New.
+type Option func(*debugLogger)
+
+// WithJSONFormat writes each log entry as a JSON object on its own line, along with the per-process invocation ID
+// and the fields attached through debugLogger.With, so that log entries of concurrent plugin executions can be
+// correlated.
+func WithJSONFormat() Option {
+	return func(l *debugLogger) {
+		l.json = true
+	}
+}
+
+// WithoutRedaction logs everything as is, including credentials bearing HTTP headers, payloads, signatures and
+// annotations, which are otherwise redacted.
+func WithoutRedaction() Option {
+	return func(l *debugLogger) {
+		l.unredacted = true
+	}
+}
+
+// WithPath writes the logs to the given file instead of notation-aws-signer/plugin.log in the user config directory.
+func WithPath(path string) Option {
+	return func(l *debugLogger) {
+		l.path = path
+	}
+}
+
+// WithLevel skips log entries less severe than the given level.
+func WithLevel(level Level) Option {
+	return func(l *debugLogger) {
+		l.level = level
+	}
+}
+
+// WithRotation rotates the log file once it grows beyond maxSize bytes and retains up to maxBackups rotated log
+// files. A maxSize of zero disables rotation.
+func WithRotation(maxSize int64, maxBackups int) Option {
+	return func(l *debugLogger) {
+		l.maxSize = maxSize
+		l.maxBackups = maxBackups
+	}
+}
+
+// ParseLevel returns the Level with the given case-insensitive name, i.e. debug, info, warn or error.
+func ParseLevel(name string) (Level, error) {
+	for level, levelName := range levelNames {
+		if strings.EqualFold(name, levelName) {
+			return level, nil
+		}
+	}
+	return LevelDebug, fmt.Errorf("unsupported log level %q, supported values are debug, info, warn and error", name)
 }
 
 // New creates a new debugLogger instance
-func New() (*debugLogger, error) {
-	cfgDir, err := userConfigDir()
-	if err != nil {
-		return nil, err
+func New(opts ...Option) (*debugLogger, error) {
+	dl := &debugLogger{
+		level:        LevelDebug,
+		maxSize:      DefaultMaxSize,
+		maxBackups:   DefaultMaxBackups,
+		invocationID: newInvocationID(),
+	}
+	for _, opt := range opts {
+		opt(dl)
 	}
 
-	path := filepath.Join(cfgDir, "notation-aws-signer", "plugin.log")
-	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
-		return nil, err
+	if dl.path == "" {
+		cfgDir, err := userConfigDir()
+		if err != nil {
+			return nil, err
+		}
+		dl.path = filepath.Join(cfgDir, "notation-aws-signer", "plugin.log")
 	}
 
-	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+	file, err := openRotatingFile(dl.path, dl.maxSize, dl.maxBackups)
 	if err != nil {
 		return nil, err
 	}
+	dl.file = file
 
-	dl := &debugLogger{
-		file: file,
+	if !dl.json {
+		dl.Infoln("-----------------------------------------------------------------------------------------")
 	}
-
-	dl.Debugln("-----------------------------------------------------------------------------------------")
-	dl.Debugf("Logs from execution of AWS signer plugin version: %s\n", version.GetVersion())
+	dl.Infof("Logs from execution of AWS signer plugin version: %s\n", version.GetVersion())
 	return dl, nil
 }
 
+// Forward creates a debugLogger which forwards log entries, redacted unless WithoutRedaction is used, to the given
+// logger instead of writing them to a log file. Fields attached through debugLogger.With aren't forwarded.
+func Forward(sink log.Logger, opts ...Option) *debugLogger {
+	dl := &debugLogger{sink: sink}
+	for _, opt := range opts {
+		opt(dl)
+	}
+	return dl
+}
+
+// With returns a logger which attaches the given field to log entries in JSON format.
+func (l *debugLogger) With(key, value string) *debugLogger {
+	if !l.enabled() || value == "" {
+		return l
+	}
+	fields := make(map[string]string, len(l.fields)+1)
+	for k, v := range l.fields {
+		fields[k] = v
+	}
+	fields[key] = value
+	withField := *l
+	withField.fields = fields
+	return &withField
+}
+
 // Close closes the logger and associated resources
 func (l *debugLogger) Close() {
 	if l.file != nil {
 		err := l.file.Close()
 		if err != nil {
-			l.Errorf("error while closing the log file", err)
+			l.Errorf("error while closing the log file: %v\n", err)
 		}
 	}
 }
@@ -87,67 +225,122 @@ func (l *debugLogger) UpdateContext(ctx context.Context) context.Context {
 
 // IsDebug returns true if Debug log is enabled
 func (l *debugLogger) IsDebug() bool {
-	return l.file != nil
+	return l.enabled() && l.level <= LevelDebug
+}
+
+func (l *debugLogger) enabled() bool {
+	return l.file != nil || l.sink != nil
 }
 
 func (l *debugLogger) Debug(args ...interface{}) {
-	l.log("DEBUG", args...)
+	l.log(LevelDebug, args...)
 }
 
 func (l *debugLogger) Debugf(format string, args ...interface{}) {
-	l.logf("DEBUG", format, args...)
+	l.logf(LevelDebug, format, args...)
 }
 
 func (l *debugLogger) Debugln(args ...interface{}) {
-	l.logln("DEBUG", args...)
+	l.logln(LevelDebug, args...)
 }
 
 func (l *debugLogger) Info(args ...interface{}) {
-	l.log("INFO", args...)
+	l.log(LevelInfo, args...)
 }
 
 func (l *debugLogger) Infof(format string, args ...interface{}) {
-	l.logf("INFO", format, args...)
+	l.logf(LevelInfo, format, args...)
 }
 
 func (l *debugLogger) Infoln(args ...interface{}) {
-	l.logln("INFO", args...)
+	l.logln(LevelInfo, args...)
 }
 
 func (l *debugLogger) Warn(args ...interface{}) {
-	l.log("WARN", args...)
+	l.log(LevelWarn, args...)
 }
 
 func (l *debugLogger) Warnf(format string, args ...interface{}) {
-	l.logf("WARN", format, args...)
+	l.logf(LevelWarn, format, args...)
 }
 
 func (l *debugLogger) Warnln(args ...interface{}) {
-	l.logln("WARN", args...)
+	l.logln(LevelWarn, args...)
 }
 
 func (l *debugLogger) Error(args ...interface{}) {
-	l.log("ERROR", args...)
+	l.log(LevelError, args...)
 }
 
 func (l *debugLogger) Errorf(format string, args ...interface{}) {
-	l.logf("ERROR", format, args...)
+	l.logf(LevelError, format, args...)
 }
 
 func (l *debugLogger) Errorln(args ...interface{}) {
-	l.logln("ERROR", args...)
+	l.logln(LevelError, args...)
 }
 
-func (l *debugLogger) logf(levelPrefix, format string, args ...interface{}) {
-	if l.file != nil {
-		_, _ = fmt.Fprintf(l.file, "%s [%s] "+format, append([]interface{}{time.Now().Format(time.RFC3339Nano), levelPrefix}, args...)...)
+func (l *debugLogger) logf(level Level, format string, args ...interface{}) {
+	if !l.enabled() || level < l.level {
+		return
+	}
+	msg := fmt.Sprintf(format, args...)
+	if !l.unredacted {
+		msg = redact(msg)
+	}
+	if l.sink != nil {
+		l.forward(level, strings.TrimRight(msg, "\n"))
+		return
+	}
+	if l.json {
+		l.logJSON(levelNames[level], msg)
+		return
+	}
+	_, _ = fmt.Fprintf(l.file, "%s [%s] %s", time.Now().Format(time.RFC3339Nano), levelNames[level], msg)
+}
+
+// logJSON writes the log entry with a single write, so that entries of concurrent plugin executions don't interleave.
+func (l *debugLogger) logJSON(level, msg string) {
+	entry := make(map[string]string, len(l.fields)+4)
+	for k, v := range l.fields {
+		entry[k] = v
+	}
+	entry[fieldTimestamp] = time.Now().Format(time.RFC3339Nano)
+	entry[fieldLevel] = level
+	entry[fieldInvocationID] = l.invocationID
+	entry[fieldMessage] = strings.TrimRight(msg, "\n")
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+	_, _ = l.file.Write(append(line, '\n'))
+}
+
+func (l *debugLogger) forward(level Level, msg string) {
+	switch level {
+	case LevelDebug:
+		l.sink.Debug(msg)
+	case LevelInfo:
+		l.sink.Info(msg)
+	case LevelWarn:
+		l.sink.Warn(msg)
+	default:
+		l.sink.Error(msg)
+	}
+}
+
+func newInvocationID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("%d-%d", os.Getpid(), time.Now().UnixNano())
 	}
+	return hex.EncodeToString(b)
 }
 
-func (l *debugLogger) log(levelPrefix string, args ...interface{}) {
-	l.logf(levelPrefix, "%v", args...)
+func (l *debugLogger) log(level Level, args ...interface{}) {
+	l.logf(level, "%v", args...)
 }
 
-func (l *debugLogger) logln(levelPrefix string, args ...interface{}) {
-	l.logf(levelPrefix, "%v\n", args...)
+func (l *debugLogger) logln(level Level, args ...interface{}) {
+	l.logf(level, "%v\n", args...)
 }
@@ -15,12 +15,16 @@ package logger
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/notaryproject/notation-plugin-framework-go/log"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -104,12 +108,271 @@ func TestDebugLogger_Error(t *testing.T) {
 	validateLogEntry(logFilePath, "[ERROR] "+msg+"\n", t)
 }
 
-func setupTestLogger(t *testing.T) (*debugLogger, string) {
+func TestDebugLogger_JSONFormat(t *testing.T) {
+	logger, logFilePath := setupTestLogger(t, WithJSONFormat())
+	logger.With(FieldOperation, "generate-envelope").With(FieldProfileArn, "").Debugf("This is a %s log!\n", "JSON")
+	logger.With(FieldRequestID, "request-id").Error("This is an Error log!")
+
+	logs, err := os.ReadFile(logFilePath)
+	if err != nil {
+		t.Fatalf("Log file not found at %s. Error: %v", logFilePath, err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(logs)), "\n")
+	var entries []map[string]string
+	for _, line := range lines[len(lines)-3:] {
+		var entry map[string]string
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("log entry %q isn't valid JSON. Error: %v", line, err)
+		}
+		entries = append(entries, entry)
+	}
+
+	invocationID := entries[0][fieldInvocationID]
+	assert.NotEmpty(t, invocationID, "invocation ID not found")
+	for _, entry := range entries {
+		assert.Equal(t, invocationID, entry[fieldInvocationID], "invocation ID mismatch")
+		_, err := time.Parse(time.RFC3339Nano, entry[fieldTimestamp])
+		assert.NoError(t, err, "invalid timestamp")
+		delete(entry, fieldInvocationID)
+		delete(entry, fieldTimestamp)
+	}
+	assert.Contains(t, entries[0][fieldMessage], "Logs from execution of AWS signer plugin version", "message mismatch")
+	assert.Equal(t, map[string]string{
+		fieldLevel:     "DEBUG",
+		fieldMessage:   "This is a JSON log!",
+		FieldOperation: "generate-envelope",
+	}, entries[1], "log entry mismatch")
+	assert.Equal(t, map[string]string{
+		fieldLevel:     "ERROR",
+		fieldMessage:   "This is an Error log!",
+		FieldRequestID: "request-id",
+	}, entries[2], "log entry mismatch")
+}
+
+func TestDebugLogger_With(t *testing.T) {
+	t.Run("DebugLoggerNotEnabled", func(t *testing.T) {
+		assert.Equal(t, discardLogger, discardLogger.With(FieldOperation, "verify-signature"), "discard logger mismatch")
+	})
+
+	t.Run("TextFormat", func(t *testing.T) {
+		logger, logFilePath := setupTestLogger(t)
+		msg := "This is a Debug log!"
+		logger.With(FieldOperation, "verify-signature").Debug(msg)
+		validateLogEntry(logFilePath, "[DEBUG] "+msg, t)
+	})
+}
+
+func TestDebugLogger_Level(t *testing.T) {
+	logger, logFilePath := setupTestLogger(t, WithLevel(LevelWarn))
+	logger.Debug("This is a Debug log!")
+	logger.Info("This is a Info log!")
+	logger.Warn("This is a Warn log!")
+	logger.Error("This is a Error log!")
+
+	logs, err := os.ReadFile(logFilePath)
+	if err != nil {
+		t.Fatalf("Log file not found at %s. Error: %v", logFilePath, err)
+	}
+	assert.NotContains(t, string(logs), "[DEBUG]", "debug log entry found")
+	assert.NotContains(t, string(logs), "[INFO]", "info log entry found")
+	assert.Contains(t, string(logs), "[WARN] This is a Warn log!", "warn log entry not found")
+	assert.Contains(t, string(logs), "[ERROR] This is a Error log!", "error log entry not found")
+	assert.False(t, logger.IsDebug(), "debug log is enabled")
+}
+
+func TestParseLevel(t *testing.T) {
+	tests := map[string]struct {
+		name   string
+		level  Level
+		errMsg string
+	}{
+		"debug":       {name: "debug", level: LevelDebug},
+		"info":        {name: "INFO", level: LevelInfo},
+		"warn":        {name: "Warn", level: LevelWarn},
+		"error":       {name: "error", level: LevelError},
+		"unsupported": {name: "trace", errMsg: "unsupported log level \"trace\", supported values are debug, info, warn and error"},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			level, err := ParseLevel(tc.name)
+			if tc.errMsg != "" {
+				assert.EqualError(t, err, tc.errMsg, "error message mismatch")
+				return
+			}
+			assert.NoError(t, err, "unexpected error")
+			assert.Equal(t, tc.level, level, "log level mismatch")
+		})
+	}
+}
+
+func TestDebugLogger_WithPath(t *testing.T) {
+	userConfigDir = func() (string, error) {
+		return "", fmt.Errorf("expected error thrown")
+	}
+	logFilePath := filepath.Join(t.TempDir(), "logs", "signer.log")
+	logger, err := New(WithPath(logFilePath))
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	defer logger.Close()
+
+	msg := "This is a Debug log!"
+	logger.Debug(msg)
+	validateLogEntry(logFilePath, "[DEBUG] "+msg, t)
+}
+
+func TestDebugLogger_Rotation(t *testing.T) {
+	logFilePath := filepath.Join(t.TempDir(), "plugin.log")
+	logger, err := New(WithPath(logFilePath), WithRotation(512, 2))
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	defer logger.Close()
+
+	for i := 0; i < 50; i++ {
+		logger.Debugf("This is Debug log number %d!\n", i)
+	}
+
+	for _, path := range []string{logFilePath, logFilePath + ".1", logFilePath + ".2"} {
+		info, err := os.Stat(path)
+		if assert.NoError(t, err, "log file not found") {
+			assert.LessOrEqual(t, info.Size(), int64(512), "log file exceeds maximum size")
+		}
+	}
+	_, err = os.Stat(logFilePath + ".3")
+	assert.True(t, os.IsNotExist(err), "unexpected rotated log file found")
+	validateLogEntry(logFilePath, "This is Debug log number 49!\n", t)
+}
+
+func TestRotatingFile_ReopenFailure(t *testing.T) {
+	logFilePath := filepath.Join(t.TempDir(), "plugin.log")
+	f, err := openRotatingFile(logFilePath, 64, 1)
+	if err != nil {
+		t.Fatalf("failed to open log file: %v", err)
+	}
+	defer f.Close()
+	entry := strings.Repeat("a", 40) + "\n"
+	_, err = f.Write([]byte(entry))
+	assert.NoError(t, err, "Write() returned error")
+
+	// another process rotated the log file, but the new log file can't be opened
+	assert.NoError(t, os.Rename(logFilePath, logFilePath+".1"))
+	assert.NoError(t, os.Mkdir(logFilePath, 0700))
+	_, err = f.Write([]byte(entry))
+	assert.NoError(t, err, "Write() returned error after failed reopen")
+	content, _ := os.ReadFile(logFilePath + ".1")
+	assert.Equal(t, entry+entry, string(content), "log entry lost after failed reopen")
+
+	// the new log file is opened by a later rotation
+	assert.NoError(t, os.Remove(logFilePath))
+	_, err = f.Write([]byte(entry))
+	assert.NoError(t, err, "Write() returned error")
+	content, _ = os.ReadFile(logFilePath)
+	assert.Equal(t, entry, string(content), "log entry not written to new log file")
+}
+
+func TestDebugLogger_ConcurrentRotation(t *testing.T) {
+	logFilePath := filepath.Join(t.TempDir(), "plugin.log")
+	const writers, entries = 8, 100
+
+	var wg sync.WaitGroup
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+		go func(writer int) {
+			defer wg.Done()
+			// every logger opens the log file separately, like concurrent plugin processes
+			logger, err := New(WithPath(logFilePath), WithRotation(4096, 1000))
+			if err != nil {
+				t.Errorf("failed to create logger: %v", err)
+				return
+			}
+			defer logger.Close()
+			for j := 0; j < entries; j++ {
+				logger.Debugf("This is Debug log number %d of writer %d!\n", j, writer)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	paths, err := filepath.Glob(logFilePath + "*")
+	if err != nil {
+		t.Fatalf("failed to list log files: %v", err)
+	}
+	count := 0
+	for _, path := range paths {
+		if strings.HasSuffix(path, ".lock") {
+			continue
+		}
+		logs, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("failed to read log file %s: %v", path, err)
+		}
+		count += strings.Count(string(logs), "This is Debug log number")
+	}
+	assert.Equal(t, writers*entries, count, "log entries lost during rotation")
+	assert.Greater(t, len(paths), 2, "log file wasn't rotated")
+}
+
+func TestDebugLogger_Redaction(t *testing.T) {
+	msg := "Authorization: AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20240131/us-west-2/signer/aws4_request\n"
+	t.Run("Redacted", func(t *testing.T) {
+		logger, logFilePath := setupTestLogger(t)
+		logger.Debug(msg)
+		validateLogEntry(logFilePath, "[DEBUG] Authorization: [REDACTED]\n", t)
+	})
+
+	t.Run("WithoutRedaction", func(t *testing.T) {
+		logger, logFilePath := setupTestLogger(t, WithoutRedaction())
+		logger.Debug(msg)
+		validateLogEntry(logFilePath, "[DEBUG] "+msg, t)
+	})
+}
+
+// captureLogger records the forwarded log entries. debugLogger only forwards to Debug, Info, Warn and Error.
+type captureLogger struct {
+	log.Logger
+	entries []string
+}
+
+func (c *captureLogger) Debug(args ...interface{}) {
+	c.entries = append(c.entries, "DEBUG "+fmt.Sprint(args...))
+}
+
+func (c *captureLogger) Info(args ...interface{}) {
+	c.entries = append(c.entries, "INFO "+fmt.Sprint(args...))
+}
+
+func (c *captureLogger) Warn(args ...interface{}) {
+	c.entries = append(c.entries, "WARN "+fmt.Sprint(args...))
+}
+
+func (c *captureLogger) Error(args ...interface{}) {
+	c.entries = append(c.entries, "ERROR "+fmt.Sprint(args...))
+}
+
+func TestForward(t *testing.T) {
+	sink := &captureLogger{}
+	logger := Forward(sink, WithLevel(LevelInfo))
+	assert.False(t, logger.IsDebug(), "IsDebug() should be false for info level")
+	logger.Debugf("dropped %d\n", 1)
+	logger.Infoln("Authorization: AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE")
+	logger.With(FieldOperation, "generate-envelope").Warnf("retrying %s\n", "SignPayload")
+	logger.Error("failed")
+	logger.Close()
+
+	assert.Equal(t, []string{
+		"INFO Authorization: [REDACTED]",
+		"WARN retrying SignPayload",
+		"ERROR failed",
+	}, sink.entries)
+}
+
+func setupTestLogger(t *testing.T, opts ...Option) (*debugLogger, string) {
 	userConfigDir = func() (string, error) {
 		return os.TempDir(), nil
 	}
 	expectedLogFilePath := filepath.Join(os.TempDir(), "notation-aws-signer", "plugin.log")
-	logger, _ := New()
+	logger, _ := New(opts...)
 	t.Cleanup(func() {
 		logger.Close()
 		os.RemoveAll(expectedLogFilePath)
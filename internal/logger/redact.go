@@ -0,0 +1,68 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package logger
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+)
+
+// minRedactedBase64Length is the length beyond which base64 values, like payloads and signatures, are truncated.
+const minRedactedBase64Length = 64
+
+// redactedBase64Prefix is the number of leading characters kept when a base64 value is truncated.
+const redactedBase64Prefix = 16
+
+var (
+	// sensitiveHeaderRegexp matches the HTTP headers carrying credentials in requests logged by the AWS SDK.
+	sensitiveHeaderRegexp = regexp.MustCompile(`(?im)^(authorization|x-amz-security-token):[^\r\n]*`)
+	// stsCredentialRegexp matches the secret access key and session token in the XML output of AWS STS AssumeRole APIs.
+	stsCredentialRegexp = regexp.MustCompile(`<(SecretAccessKey|SessionToken)>[^<]*</`)
+	// webIdentityTokenRegexp matches the OIDC token in the form encoded input of AssumeRoleWithWebIdentity API.
+	webIdentityTokenRegexp = regexp.MustCompile(`(WebIdentityToken=)[^&\s]*`)
+	// annotationsRegexp matches the annotations of GenerateEnvelopeResponse and the metadata of SignPayload output.
+	annotationsRegexp = regexp.MustCompile(`"(?:annotations|metadata)"\s*:\s*\{[^{}]*\}`)
+	// jsonMemberRegexp matches the JSON object members with string values.
+	jsonMemberRegexp = regexp.MustCompile(`("(?:[^"\\]|\\.)*"\s*:\s*)"((?:[^"\\]|\\.)*)"`)
+	// base64MemberRegexp matches the JSON object members with base64 values, i.e. the payload and signature of
+	// GenerateSignatureRequest and SignPayload input/output, and the signature envelope of GenerateEnvelopeResponse.
+	base64MemberRegexp = regexp.MustCompile(`("(?:payload|signature|signatureEnvelope)"\s*:\s*)"([A-Za-z0-9+/_-]+={0,2})"`)
+)
+
+// redact masks the sensitive data in the given log message: credentials bearing HTTP headers, AWS STS credentials and
+// web identity tokens are masked, annotation values are replaced with their SHA-256 hash and base64 payloads,
+// signatures and signature envelopes are truncated.
+// Other values, like digests, ARNs and file paths, aren't sensitive and are kept.
+func redact(msg string) string {
+	msg = sensitiveHeaderRegexp.ReplaceAllString(msg, "$1: [REDACTED]")
+	msg = stsCredentialRegexp.ReplaceAllString(msg, "<$1>[REDACTED]</")
+	msg = webIdentityTokenRegexp.ReplaceAllString(msg, "${1}[REDACTED]")
+	msg = annotationsRegexp.ReplaceAllStringFunc(msg, func(annotations string) string {
+		return jsonMemberRegexp.ReplaceAllStringFunc(annotations, func(member string) string {
+			parts := jsonMemberRegexp.FindStringSubmatch(member)
+			hash := sha256.Sum256([]byte(parts[2]))
+			return fmt.Sprintf(`%s"sha256:%s"`, parts[1], hex.EncodeToString(hash[:]))
+		})
+	})
+	return base64MemberRegexp.ReplaceAllStringFunc(msg, func(member string) string {
+		parts := base64MemberRegexp.FindStringSubmatch(member)
+		val := parts[2]
+		if len(val) < minRedactedBase64Length {
+			return member
+		}
+		return fmt.Sprintf(`%s"%s...[%d characters truncated]"`, parts[1], val[:redactedBase64Prefix], len(val)-redactedBase64Prefix)
+	})
+}
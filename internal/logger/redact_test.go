@@ -0,0 +1,94 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package logger
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedact(t *testing.T) {
+	payload := strings.Repeat("eyJ0YXJnZXRBcnRpZmFjdCI6", 4)
+	digest := "sha384:" + strings.Repeat("0123456789abcdef", 6)
+	profileVersionArn := "arn:aws:signer:us-west-2:123456789012:/signing-profiles/platform_team_release_signing_profile/abcdEFGH12"
+	filePath := "/var/lib/notation/plugins/com.amazonaws.signer.notation.plugin/revocation_bundles/production-bundle.json"
+	tests := map[string]struct {
+		msg      string
+		expected string
+	}{
+		"authorizationHeader": {
+			msg:      "Request\r\nPOST /signing-jobs/with-payload HTTP/1.1\r\nAuthorization: AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20240131/us-west-2/signer/aws4_request, Signature=abc\r\nX-Amz-Date: 20240131T000000Z\r\n",
+			expected: "Request\r\nPOST /signing-jobs/with-payload HTTP/1.1\r\nAuthorization: [REDACTED]\r\nX-Amz-Date: 20240131T000000Z\r\n",
+		},
+		"securityTokenHeader": {
+			msg:      "Host: signer.us-west-2.amazonaws.com\nx-amz-security-token: FwoGZXIvYXdzEJr\n",
+			expected: "Host: signer.us-west-2.amazonaws.com\nx-amz-security-token: [REDACTED]\n",
+		},
+		"assumeRoleResponse": {
+			msg:      "Response\nHTTP/1.1 200 OK\r\n\r\n<AssumeRoleResponse xmlns=\"https://sts.amazonaws.com/doc/2011-06-15/\"><AssumeRoleResult><Credentials><AccessKeyId>ASIAEXAMPLE</AccessKeyId><SecretAccessKey>wJalrXUtnFEMI/K7MDENG/bPxRfiCYEXAMPLEKEY</SecretAccessKey><SessionToken>FwoGZXIvYXdzEJr//////////wEaDExample</SessionToken><Expiration>2024-01-31T01:00:00Z</Expiration></Credentials></AssumeRoleResult></AssumeRoleResponse>",
+			expected: "Response\nHTTP/1.1 200 OK\r\n\r\n<AssumeRoleResponse xmlns=\"https://sts.amazonaws.com/doc/2011-06-15/\"><AssumeRoleResult><Credentials><AccessKeyId>ASIAEXAMPLE</AccessKeyId><SecretAccessKey>[REDACTED]</SecretAccessKey><SessionToken>[REDACTED]</SessionToken><Expiration>2024-01-31T01:00:00Z</Expiration></Credentials></AssumeRoleResult></AssumeRoleResponse>",
+		},
+		"webIdentityToken": {
+			msg:      "Action=AssumeRoleWithWebIdentity&RoleArn=arn%3Aaws%3Aiam%3A%3A123456789012%3Arole%2FSigner&RoleSessionName=notation&Version=2011-06-15&WebIdentityToken=eyJhbGciOiJSUzI1NiJ9.eyJzdWIiOiJjaSJ9.c2ln",
+			expected: "Action=AssumeRoleWithWebIdentity&RoleArn=arn%3Aaws%3Aiam%3A%3A123456789012%3Arole%2FSigner&RoleSessionName=notation&Version=2011-06-15&WebIdentityToken=[REDACTED]",
+		},
+		"base64Payload": {
+			msg:      `{"payload":"` + payload + `","payloadFormat":"application/vnd.oci.descriptor.v1+json"}`,
+			expected: `{"payload":"eyJ0YXJnZXRBcnRp...[80 characters truncated]","payloadFormat":"application/vnd.oci.descriptor.v1+json"}`,
+		},
+		"base64SignatureEnvelope": {
+			msg:      `{"signatureEnvelope": "` + payload + `==","signatureEnvelopeType":"application/jose+json"}`,
+			expected: `{"signatureEnvelope": "eyJ0YXJnZXRBcnRp...[82 characters truncated]","signatureEnvelopeType":"application/jose+json"}`,
+		},
+		"shortSignature": {
+			msg:      `{"jobId":"job","signature":"MEUCIQ=="}`,
+			expected: `{"jobId":"job","signature":"MEUCIQ=="}`,
+		},
+		"profileVersionArn": {
+			msg:      "signing profile version: " + profileVersionArn,
+			expected: "signing profile version: " + profileVersionArn,
+		},
+		"profileVersionArnInJSON": {
+			msg:      `{"profileVersionArn":"` + profileVersionArn + `"}`,
+			expected: `{"profileVersionArn":"` + profileVersionArn + `"}`,
+		},
+		"filePath": {
+			msg:      "loading revocation bundle from " + filePath,
+			expected: "loading revocation bundle from " + filePath,
+		},
+		"hexDigest": {
+			msg:      "digest: " + digest,
+			expected: "digest: " + digest,
+		},
+		"annotations": {
+			msg:      `{"annotations":{"com.amazonaws.signer.signingJob":"job","user":"value"},"signatureEnvelopeType":"application/jose+json"}`,
+			expected: `{"annotations":{"com.amazonaws.signer.signingJob":"sha256:5e8c9902207afaeb7120430c585a445f21e92932081d64bc99f80e4925bcb002","user":"sha256:cd42404d52ad55ccfa9aca4adc828aa5800ad9d385a0671fbcbf724118320619"},"signatureEnvelopeType":"application/jose+json"}`,
+		},
+		"metadata": {
+			msg:      `{"jobId":"job","metadata":{"key":"value"}}`,
+			expected: `{"jobId":"job","metadata":{"key":"sha256:cd42404d52ad55ccfa9aca4adc828aa5800ad9d385a0671fbcbf724118320619"}}`,
+		},
+		"nothingToRedact": {
+			msg:      "calling AWS Signer's SignPayload API",
+			expected: "calling AWS Signer's SignPayload API",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, redact(tc.msg), "redacted message mismatch")
+		})
+	}
+}
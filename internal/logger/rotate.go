@@ -0,0 +1,140 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package logger
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+)
+
+// rotatingFile is a log file which is rotated once it grows beyond maxSize bytes, retaining up to maxBackups rotated
+// files named <path>.1 (most recent) to <path>.<maxBackups>. Several plugin processes can write to the same
+// rotatingFile concurrently: every write is a single append, and rotation is serialized across processes through an
+// exclusive lock on <path>.lock.
+type rotatingFile struct {
+	path       string
+	maxSize    int64
+	maxBackups int
+	file       *os.File
+}
+
+func openRotatingFile(path string, maxSize int64, maxBackups int) (*rotatingFile, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, err
+	}
+	file, err := openLogFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return &rotatingFile{
+		path:       path,
+		maxSize:    maxSize,
+		maxBackups: maxBackups,
+		file:       file,
+	}, nil
+}
+
+// Write appends p to the log file, rotating the log file first if p doesn't fit in it. Rotation failures aren't
+// returned, since losing the log entry is worse than exceeding the maximum size.
+func (f *rotatingFile) Write(p []byte) (int, error) {
+	if f.maxSize > 0 {
+		if info, err := f.file.Stat(); err == nil && info.Size() > 0 && info.Size()+int64(len(p)) > f.maxSize {
+			_ = f.rotate(info)
+		}
+	}
+	return f.file.Write(p)
+}
+
+func (f *rotatingFile) Close() error {
+	return f.file.Close()
+}
+
+// rotate rotates the log file described by info, unless another process already rotated it, in which case the new
+// log file is opened instead.
+func (f *rotatingFile) rotate(info os.FileInfo) error {
+	lock, err := openLogFile(f.path + ".lock")
+	if err != nil {
+		return err
+	}
+	defer lock.Close()
+	if err := lockFile(lock); err != nil {
+		return err
+	}
+	defer unlockFile(lock)
+
+	var shiftErr error
+	closed := false
+	if current, err := os.Stat(f.path); err == nil && os.SameFile(info, current) {
+		// On Windows, files are opened without FILE_SHARE_DELETE, so renaming the log file fails while any handle to
+		// it is open, including the one of this process. Hence, there the log file is closed before it's renamed.
+		if runtime.GOOS == "windows" {
+			_ = f.file.Close()
+			closed = true
+		}
+		shiftErr = f.shiftBackups()
+	}
+
+	file, err := openLogFile(f.path)
+	if err != nil {
+		if closed {
+			// keep writing to the log file, wherever shiftBackups left it
+			if file, reopenErr := openLogFile(f.rotatedPath(shiftErr)); reopenErr == nil {
+				f.file = file
+			}
+		}
+		return err
+	}
+	if !closed {
+		_ = f.file.Close()
+	}
+	f.file = file
+	return shiftErr
+}
+
+// rotatedPath returns the path of the log file after shiftBackups returned shiftErr, i.e. <path>.1 unless renaming
+// the log file failed.
+func (f *rotatingFile) rotatedPath(shiftErr error) string {
+	if shiftErr != nil || f.maxBackups < 1 {
+		return f.path
+	}
+	return f.backupPath(1)
+}
+
+// shiftBackups renames <path>.<n> to <path>.<n+1>, dropping the oldest rotated file, and the log file to <path>.1.
+// On Windows, renaming the log file still fails while another process has it open, in which case the log file is
+// rotated by a later write.
+func (f *rotatingFile) shiftBackups() error {
+	if f.maxBackups < 1 {
+		return os.Remove(f.path)
+	}
+	if err := os.Remove(f.backupPath(f.maxBackups)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	for i := f.maxBackups - 1; i > 0; i-- {
+		if err := os.Rename(f.backupPath(i), f.backupPath(i+1)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return os.Rename(f.path, f.backupPath(1))
+}
+
+func (f *rotatingFile) backupPath(n int) string {
+	return fmt.Sprintf("%s.%d", f.path, n)
+}
+
+func openLogFile(path string) (*os.File, error) {
+	return os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+}
@@ -0,0 +1,84 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// Package partition provides AWS partition information required to validate AWS Signer ARNs and endpoints.
+package partition
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
+)
+
+const (
+	AWS      = "aws"
+	AWSCN    = "aws-cn"
+	AWSUSGov = "aws-us-gov"
+	AWSISO   = "aws-iso"
+	AWSISOB  = "aws-iso-b"
+	AWSISOE  = "aws-iso-e"
+	AWSISOF  = "aws-iso-f"
+)
+
+// regionPrefixes maps region prefixes to partitions. Regions not matching any prefix belong to AWS partition.
+// Longer prefixes must come first.
+var regionPrefixes = []struct {
+	prefix    string
+	partition string
+}{
+	{prefix: "us-isob-", partition: AWSISOB},
+	{prefix: "us-isof-", partition: AWSISOF},
+	{prefix: "eu-isoe-", partition: AWSISOE},
+	{prefix: "us-iso-", partition: AWSISO},
+	{prefix: "us-gov-", partition: AWSUSGov},
+	{prefix: "cn-", partition: AWSCN},
+}
+
+// All contains all supported partitions.
+var All = []string{AWS, AWSCN, AWSUSGov, AWSISO, AWSISOB, AWSISOE, AWSISOF}
+
+// ForRegion returns the partition of given region.
+func ForRegion(region string) string {
+	region = strings.ToLower(region)
+	for _, p := range regionPrefixes {
+		if strings.HasPrefix(region, p.prefix) {
+			return p.partition
+		}
+	}
+	return AWS
+}
+
+// ValidateARN checks that partition of given ARN is supported and consistent with its region.
+// Region check is skipped if anyRegion is the ARN's region, e.g. for identity patterns.
+func ValidateARN(a arn.ARN, anyRegion string) error {
+	if !isSupported(a.Partition) {
+		return fmt.Errorf("partition %q is not supported", a.Partition)
+	}
+	if a.Region == "" || a.Region == anyRegion {
+		return nil
+	}
+	if p := ForRegion(a.Region); p != a.Partition {
+		return fmt.Errorf("region %q belongs to partition %q, not %q", a.Region, p, a.Partition)
+	}
+	return nil
+}
+
+func isSupported(partition string) bool {
+	for _, p := range All {
+		if p == partition {
+			return true
+		}
+	}
+	return false
+}
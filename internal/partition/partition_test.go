@@ -0,0 +1,68 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package partition
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
+	"github.com/stretchr/testify/assert"
+)
+
+var testRegions = map[string]string{
+	AWS:      "us-west-2",
+	AWSCN:    "cn-north-1",
+	AWSUSGov: "us-gov-west-1",
+	AWSISO:   "us-iso-east-1",
+	AWSISOB:  "us-isob-east-1",
+	AWSISOE:  "eu-isoe-west-1",
+	AWSISOF:  "us-isof-south-1",
+}
+
+func TestForRegion(t *testing.T) {
+	for _, partition := range All {
+		t.Run(partition, func(t *testing.T) {
+			assert.Equal(t, partition, ForRegion(testRegions[partition]))
+		})
+	}
+	assert.Equal(t, AWS, ForRegion("eu-central-1"))
+	assert.Equal(t, AWSCN, ForRegion("CN-NORTHWEST-1"))
+}
+
+func TestValidateARN(t *testing.T) {
+	for _, partition := range All {
+		t.Run(partition, func(t *testing.T) {
+			a := arn.ARN{Partition: partition, Service: "signer", Region: testRegions[partition], AccountID: "111122223333", Resource: "/signing-profiles/Profile"}
+			assert.NoError(t, ValidateARN(a, ""))
+
+			a.Region = "*"
+			assert.NoError(t, ValidateARN(a, "*"))
+		})
+	}
+}
+
+func TestValidateARN_Error(t *testing.T) {
+	tests := map[string]arn.ARN{
+		"unknownPartition":      {Partition: "aws-mars", Region: "mars-1"},
+		"chinaRegionInAWS":      {Partition: AWS, Region: "cn-north-1"},
+		"govCloudRegionInAWS":   {Partition: AWS, Region: "us-gov-west-1"},
+		"commercialRegionInCN":  {Partition: AWSCN, Region: "us-east-1"},
+		"commercialRegionInGov": {Partition: AWSUSGov, Region: "us-west-2"},
+	}
+	for name, a := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Error(t, ValidateARN(a, ""), "ValidateARN() expected error but not found")
+		})
+	}
+}
@@ -0,0 +1,96 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package signer
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-signer-notation-plugin/internal/client"
+	"github.com/aws/aws-signer-notation-plugin/internal/logger"
+	"github.com/aws/aws-signer-notation-plugin/internal/tracing"
+
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
+	"github.com/aws/aws-sdk-go-v2/service/signer"
+	"github.com/aws/aws-sdk-go-v2/service/signer/types"
+
+	"github.com/notaryproject/notation-plugin-framework-go/plugin"
+)
+
+const (
+	errorMsgExpiryTooShortFmt          = "requested signature expiry of %d seconds is shorter than the signature validity period of %d %s (%d seconds) of signing profile %s. Please use a signing profile with shorter signature validity period or increase the expiry."
+	errorMsgUnsupportedValidityTypeFmt = "signing profile %s uses unsupported signature validity period type %q."
+
+	annotationSignatureExpiry = "com.amazonaws.signer.signatureExpiry"
+)
+
+// defaultSignatureValidityPeriod is the signature validity period AWS Signer uses for signing profiles created
+// without one.
+var defaultSignatureValidityPeriod = &types.SignatureValidityPeriod{Type: types.ValidityTypeMonths, Value: 135}
+
+// getSignatureExpiry returns the expiry of signature generated using the given signing profile. AWS Signer sets the
+// signature expiry from the signature validity period of the signing profile, so the requested expiry is accepted
+// only if it is equal to or longer than the signature validity period.
+func (s *Signer) getSignatureExpiry(ctx context.Context, expiryDurationInSeconds uint64, signingProfileArn arn.ARN, signingProfileName string) (time.Time, error) {
+	log := logger.GetLogger(ctx)
+
+	log.Debug("calling AWS Signer's GetSigningProfile API")
+	profileCtx, profileSpan := tracing.Start(ctx, "Signer.GetSigningProfile", tracing.ProfileAttributes(signingProfileArn.String())...)
+	output, err := s.awssigner.GetSigningProfile(profileCtx, &signer.GetSigningProfileInput{
+		ProfileName:  &signingProfileName,
+		ProfileOwner: &signingProfileArn.AccountID,
+	})
+	tracing.End(profileSpan, err)
+	if err != nil {
+		log.With(logger.FieldRequestID, client.RequestID(err)).Debugf("failed AWS Signer's GetSigningProfile API call with error: %v", err)
+		return time.Time{}, parseAwsError(err)
+	}
+	log = log.With(logger.FieldRequestID, client.ResultRequestID(output.ResultMetadata))
+
+	validityPeriod := output.SignatureValidityPeriod
+	if validityPeriod == nil {
+		validityPeriod = defaultSignatureValidityPeriod
+	}
+	now := s.now()
+	var expiry time.Time
+	switch validityPeriod.Type {
+	case types.ValidityTypeDays:
+		expiry = now.AddDate(0, 0, int(validityPeriod.Value))
+	case types.ValidityTypeMonths:
+		expiry = now.AddDate(0, int(validityPeriod.Value), 0)
+	case types.ValidityTypeYears:
+		expiry = now.AddDate(int(validityPeriod.Value), 0, 0)
+	default:
+		return time.Time{}, plugin.NewGenericErrorf(errorMsgUnsupportedValidityTypeFmt, signingProfileArn, validityPeriod.Type)
+	}
+	log.Debugf("succeeded AWS Signer's GetSigningProfile API call. signature validity period: %d %s", validityPeriod.Value, validityPeriod.Type)
+
+	validityInSeconds := uint64(expiry.Sub(now) / time.Second)
+	if expiryDurationInSeconds < validityInSeconds {
+		return time.Time{}, plugin.NewValidationErrorf(errorMsgExpiryTooShortFmt, expiryDurationInSeconds,
+			validityPeriod.Value, strings.ToLower(string(validityPeriod.Type)), validityInSeconds, signingProfileArn)
+	}
+	return expiry, nil
+}
+
+// withSignatureExpiry returns a copy of annotations along with the effective signature expiry.
+func withSignatureExpiry(annotations map[string]string, expiry time.Time) map[string]string {
+	res := make(map[string]string, len(annotations)+1)
+	for k, v := range annotations {
+		res[k] = v
+	}
+	res[annotationSignatureExpiry] = expiry.UTC().Format(time.RFC3339)
+	return res
+}
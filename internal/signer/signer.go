@@ -17,13 +17,18 @@ package signer
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/aws/aws-signer-notation-plugin/internal/client"
 	"github.com/aws/aws-signer-notation-plugin/internal/logger"
+	"github.com/aws/aws-signer-notation-plugin/internal/partition"
+	"github.com/aws/aws-signer-notation-plugin/internal/tracing"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/aws/arn"
 	"github.com/aws/aws-sdk-go-v2/aws/transport/http"
 	"github.com/aws/aws-sdk-go-v2/service/signer"
@@ -35,40 +40,86 @@ import (
 const (
 	mediaTypeJwsEnvelope               = "application/jose+json"
 	errorMsgMalformedSigningProfileFmt = "%s is not a valid AWS Signer signing profile or signing profile version ARN."
-	errorMSGExpiryPassed               = "AWSSigner plugin doesn't support -e (--expiry) argument. Please use signing profile to set signature expiry."
+	errorMsgUnsupportedPlatformFmt     = "signing profile %s uses platform %q which is not supported by AWSSigner plugin."
+	errorMsgInvalidPartitionFmt        = "%s is not a valid AWS Signer signing profile ARN: %v."
+	errorMsgProfileVersionMismatchFmt  = "signing profile version %s was requested but AWS Signer signed with signing profile version %q."
+
+	annotationSigningProfileVersion = "com.amazonaws.signer.signingProfileVersion"
+
+	platformNotation = "Notation-OCI-SHA384-ECDSA"
 )
 
+// platformKeySpecs maps AWS Signer signing platforms to the key spec used by the platform.
+var platformKeySpecs = map[string]plugin.KeySpec{
+	platformNotation: plugin.KeySpecEC384,
+}
+
 // Signer generates signature generated using AWS Signer.
 type Signer struct {
 	awssigner client.Interface
+	clock     func() time.Time
+}
+
+// Option configures the Signer created by New.
+type Option func(*Signer)
+
+// WithClock uses the given clock instead of the system clock, e.g. to compute the signature expiry.
+func WithClock(clock func() time.Time) Option {
+	return func(s *Signer) {
+		s.clock = clock
+	}
 }
 
 // New returns Signer given an AWS Signer client.
-func New(s client.Interface) *Signer {
-	return &Signer{awssigner: s}
+func New(s client.Interface, opts ...Option) *Signer {
+	sig := &Signer{awssigner: s}
+	for _, opt := range opts {
+		opt(sig)
+	}
+	return sig
+}
+
+func (s *Signer) now() time.Time {
+	if s.clock != nil {
+		return s.clock()
+	}
+	return time.Now()
 }
 
 // GenerateEnvelope generates signature envelope by calling AWS Signer
-func (s *Signer) GenerateEnvelope(ctx context.Context, request *plugin.GenerateEnvelopeRequest) (*plugin.GenerateEnvelopeResponse, error) {
+func (s *Signer) GenerateEnvelope(ctx context.Context, request *plugin.GenerateEnvelopeRequest) (_ *plugin.GenerateEnvelopeResponse, err error) {
+	ctx, span := tracing.Start(ctx, "GenerateEnvelope")
+	defer func() { tracing.End(span, err) }()
 	log := logger.GetLogger(ctx)
 
 	log.Debug("validating request")
-	if err := validate(request); err != nil {
+	_, validationSpan := tracing.Start(ctx, "ValidateRequest")
+	err = validate(request)
+	tracing.End(validationSpan, err)
+	if err != nil {
 		return nil, err
 	}
 	log.Debug("succeeded request validation")
 
+	log = log.With(logger.FieldProfileArn, request.KeyID)
+	ctx = log.UpdateContext(ctx)
 	log.Debug("validating signing profile")
-	signingProfileArn, err := arn.Parse(request.KeyID)
-	if err != nil {
-		return nil, plugin.NewValidationErrorf(errorMsgMalformedSigningProfileFmt, request.KeyID)
-	}
-	signingProfileName, err := getProfileName(signingProfileArn)
+	signingProfileArn, signingProfileName, signingProfileVersion, err := tracedParseSigningProfileArn(ctx, request.KeyID)
 	if err != nil {
 		return nil, err
 	}
+	span.SetAttributes(tracing.ProfileAttributes(request.KeyID)...)
 	log.Debug("succeeded signing profile validation")
 
+	var expiry time.Time
+	if request.ExpiryDurationInSeconds != 0 {
+		log.Debug("validating signature expiry")
+		if expiry, err = s.getSignatureExpiry(ctx, request.ExpiryDurationInSeconds, signingProfileArn, signingProfileName); err != nil {
+			return nil, err
+		}
+		log.Debugf("succeeded signature expiry validation. effective expiry: %s", expiry)
+	}
+
 	log.Debug("calling AWS Signer's SignPayload API")
 	input := &signer.SignPayloadInput{
 		Payload:       request.Payload,
@@ -76,37 +127,126 @@ func (s *Signer) GenerateEnvelope(ctx context.Context, request *plugin.GenerateE
 		PayloadFormat: &request.PayloadType,
 		ProfileOwner:  &signingProfileArn.AccountID,
 	}
-	output, err := s.awssigner.SignPayload(ctx, input)
+	signCtx, signSpan := tracing.Start(ctx, "Signer.SignPayload", tracing.ProfileAttributes(request.KeyID)...)
+	output, err := s.awssigner.SignPayload(signCtx, input)
+	tracing.End(signSpan, err)
 	if err != nil {
-		log.Debugf("failed AWS Signer's SignPayload API call with error: %v", err)
+		log.With(logger.FieldRequestID, client.RequestID(err)).Debugf("failed AWS Signer's SignPayload API call with error: %v", err)
 		return nil, parseAwsError(err)
 	}
+	log = log.With(logger.FieldRequestID, client.ResultRequestID(output.ResultMetadata))
+
+	// AWS Signer always signs with the active version of the signing profile, so when the KeyID pins a signing
+	// profile version, the version reported in the signature metadata must be the requested one.
+	if signingProfileVersion != "" {
+		if signedVersion := output.Metadata[annotationSigningProfileVersion]; signedVersion != request.KeyID {
+			log.Debugf("signing profile version mismatch. requested: %s, signed: %s", request.KeyID, signedVersion)
+			return nil, plugin.NewValidationErrorf(errorMsgProfileVersionMismatchFmt, request.KeyID, signedVersion)
+		}
+	}
 
 	res := &plugin.GenerateEnvelopeResponse{
 		SignatureEnvelope:     output.Signature,
 		SignatureEnvelopeType: request.SignatureEnvelopeType,
 		Annotations:           output.Metadata}
-	log.Debugf("succeeded AWS Signer's SignPayload API call. output: %s", res)
+	if !expiry.IsZero() {
+		res.Annotations = withSignatureExpiry(output.Metadata, expiry)
+	}
+	if log.IsDebug() {
+		resJSON, _ := json.Marshal(res)
+		log.Debugf("succeeded AWS Signer's SignPayload API call. output: %s", resJSON)
+	}
 
 	return res, nil
 }
 
-func getProfileName(arn arn.ARN) (string, error) {
-	//resource name will be in format /signing-profiles/ProfileName
+// DescribeKey returns the key spec of the signing profile identified by request.KeyID by calling AWS Signer.
+func (s *Signer) DescribeKey(ctx context.Context, request *plugin.DescribeKeyRequest) (_ *plugin.DescribeKeyResponse, err error) {
+	ctx, span := tracing.Start(ctx, "DescribeKey")
+	defer func() { tracing.End(span, err) }()
+	log := logger.GetLogger(ctx)
+
+	if request.ContractVersion != plugin.ContractVersion {
+		return nil, plugin.NewUnsupportedContractVersionError(request.ContractVersion)
+	}
+
+	log = log.With(logger.FieldProfileArn, request.KeyID)
+	log.Debug("validating signing profile")
+	signingProfileArn, signingProfileName, _, err := tracedParseSigningProfileArn(ctx, request.KeyID)
+	if err != nil {
+		return nil, err
+	}
+	span.SetAttributes(tracing.ProfileAttributes(request.KeyID)...)
+	log.Debug("succeeded signing profile validation")
+
+	log.Debug("calling AWS Signer's GetSigningProfile API")
+	input := &signer.GetSigningProfileInput{
+		ProfileName:  &signingProfileName,
+		ProfileOwner: &signingProfileArn.AccountID,
+	}
+	profileCtx, profileSpan := tracing.Start(ctx, "Signer.GetSigningProfile", tracing.ProfileAttributes(request.KeyID)...)
+	output, err := s.awssigner.GetSigningProfile(profileCtx, input)
+	tracing.End(profileSpan, err)
+	if err != nil {
+		log.With(logger.FieldRequestID, client.RequestID(err)).Debugf("failed AWS Signer's GetSigningProfile API call with error: %v", err)
+		return nil, parseAwsError(err)
+	}
+	log.With(logger.FieldRequestID, client.ResultRequestID(output.ResultMetadata)).Debugf("succeeded AWS Signer's GetSigningProfile API call. platform: %s", aws.ToString(output.PlatformId))
+
+	keySpec, ok := platformKeySpecs[aws.ToString(output.PlatformId)]
+	if !ok {
+		return nil, plugin.NewValidationErrorf(errorMsgUnsupportedPlatformFmt, request.KeyID, aws.ToString(output.PlatformId))
+	}
+
+	return &plugin.DescribeKeyResponse{
+		KeyID:   request.KeyID,
+		KeySpec: keySpec,
+	}, nil
+}
+
+// tracedParseSigningProfileArn calls parseSigningProfileArn within a span.
+func tracedParseSigningProfileArn(ctx context.Context, keyID string) (arn.ARN, string, string, error) {
+	_, span := tracing.Start(ctx, "ParseSigningProfileArn", tracing.AttrProfileArn.String(keyID))
+	signingProfileArn, signingProfileName, signingProfileVersion, err := parseSigningProfileArn(keyID)
+	tracing.End(span, err)
+	return signingProfileArn, signingProfileName, signingProfileVersion, err
+}
+
+// parseSigningProfileArn parses the given KeyID and returns the signing profile ARN along with the profile name and
+// the profile version. The profile version is empty if KeyID is a signing profile ARN.
+func parseSigningProfileArn(keyID string) (arn.ARN, string, string, error) {
+	signingProfileArn, err := arn.Parse(keyID)
+	if err != nil {
+		return arn.ARN{}, "", "", plugin.NewValidationErrorf(errorMsgMalformedSigningProfileFmt, keyID)
+	}
+	if err := partition.ValidateARN(signingProfileArn, ""); err != nil {
+		return arn.ARN{}, "", "", plugin.NewValidationErrorf(errorMsgInvalidPartitionFmt, keyID, err)
+	}
+	signingProfileName, signingProfileVersion, err := getProfileNameAndVersion(signingProfileArn)
+	if err != nil {
+		return arn.ARN{}, "", "", err
+	}
+	return signingProfileArn, signingProfileName, signingProfileVersion, nil
+}
+
+func getProfileNameAndVersion(arn arn.ARN) (string, string, error) {
+	//resource name will be in format /signing-profiles/ProfileName or /signing-profiles/ProfileName/ProfileVersion
 	profileArnParts := strings.Split(arn.Resource, "/")
-	if len(profileArnParts) != 3 {
-		return "", plugin.NewValidationErrorf(errorMsgMalformedSigningProfileFmt, arn)
+	switch {
+	case len(profileArnParts) == 3 && profileArnParts[2] != "":
+		return profileArnParts[2], "", nil
+	case len(profileArnParts) == 4 && profileArnParts[2] != "" && profileArnParts[3] != "":
+		return profileArnParts[2], profileArnParts[3], nil
+	default:
+		return "", "", plugin.NewValidationErrorf(errorMsgMalformedSigningProfileFmt, arn)
 	}
-	return profileArnParts[2], nil
 }
 
 func validate(request *plugin.GenerateEnvelopeRequest) error {
-	if request.ExpiryDurationInSeconds != 0 {
-		return plugin.NewError(plugin.ErrorCodeValidation, errorMSGExpiryPassed)
-	}
 	if request.ContractVersion != plugin.ContractVersion {
 		return plugin.NewUnsupportedContractVersionError(request.ContractVersion)
 	}
+	// SignPayload API only generates JWS envelopes, so other envelope types, e.g. application/cose, are rejected
 	if request.SignatureEnvelopeType != mediaTypeJwsEnvelope {
 		return plugin.NewUnsupportedError(fmt.Sprintf("envelope type %q", request.SignatureEnvelopeType))
 	}
@@ -123,16 +263,7 @@ func parseAwsError(err error) *plugin.Error {
 			errMsgSuffix = fmt.Sprintf(" RequestID: %s.", re.ServiceRequestID())
 		}
 		errMsg := fmt.Sprintf("Failed to call AWSSigner. Error: %s.%s", apiError.ErrorMessage(), errMsgSuffix)
-		switch apiError.ErrorCode() {
-		case "NotFoundException", "ResourceNotFoundException", "ValidationException", "BadRequestException":
-			return plugin.NewValidationError(errMsg)
-		case "ThrottlingException":
-			return plugin.NewError(plugin.ErrorCodeThrottled, errMsg)
-		case "AccessDeniedException":
-			return plugin.NewError(plugin.ErrorCodeAccessDenied, errMsg)
-		default:
-			return plugin.NewGenericError(errMsg)
-		}
+		return plugin.NewError(client.ErrorCode(err), errMsg)
 	}
 	return plugin.NewGenericError(err.Error())
 }
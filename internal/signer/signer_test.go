@@ -16,17 +16,27 @@ package signer
 import (
 	"context"
 	"fmt"
+	"math"
 	nethttp "net/http"
 	"testing"
+	"time"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/aws/transport/http"
 	"github.com/aws/aws-sdk-go-v2/service/signer"
+	"github.com/aws/aws-sdk-go-v2/service/signer/types"
 	"github.com/aws/aws-signer-notation-plugin/internal/client"
+	"github.com/aws/aws-signer-notation-plugin/internal/tracing"
 	"github.com/aws/smithy-go"
 	smithyhttp "github.com/aws/smithy-go/transport/http"
 	"github.com/golang/mock/gomock"
 	"github.com/notaryproject/notation-plugin-framework-go/plugin"
 	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
 )
 
 const testProfile = "NotationProfile"
@@ -62,10 +72,153 @@ func TestGenerateEnvelope(t *testing.T) {
 	assert.Equal(t, map[string]string{"metadatakey": "metadatavalue"}, response.Annotations, "metadata mismatch")
 }
 
+func TestGenerateEnvelope_Partitions(t *testing.T) {
+	tests := map[string]string{
+		"aws":        "arn:aws:signer:us-west-2:780792624090:/signing-profiles/NotationProfile",
+		"aws-cn":     "arn:aws-cn:signer:cn-north-1:780792624090:/signing-profiles/NotationProfile",
+		"aws-us-gov": "arn:aws-us-gov:signer:us-gov-west-1:780792624090:/signing-profiles/NotationProfile",
+		"aws-iso":    "arn:aws-iso:signer:us-iso-east-1:780792624090:/signing-profiles/NotationProfile",
+		"aws-iso-b":  "arn:aws-iso-b:signer:us-isob-east-1:780792624090:/signing-profiles/NotationProfile",
+		"aws-iso-e":  "arn:aws-iso-e:signer:eu-isoe-west-1:780792624090:/signing-profiles/NotationProfile",
+		"aws-iso-f":  "arn:aws-iso-f:signer:us-isof-south-1:780792624090:/signing-profiles/NotationProfile",
+	}
+	for name, keyID := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			mockSignerClient := client.NewMockInterface(mockCtrl)
+			mockSignerClient.EXPECT().SignPayload(gomock.Any(), gomock.Any()).Return(&signer.SignPayloadOutput{Signature: testSig}, nil)
+
+			req := mockGenerateEnvReq()
+			req.KeyID = keyID
+			_, err := New(mockSignerClient).GenerateEnvelope(context.TODO(), req)
+			assert.NoError(t, err, "GenerateEnvelope() returned error")
+		})
+	}
+}
+
+func TestGenerateEnvelope_ProfileVersion(t *testing.T) {
+	const profileVersionArn = "arn:aws:signer:us-west-2:780792624090:/signing-profiles/NotationProfile/abc123"
+	tests := map[string]struct {
+		metadata map[string]string
+		errorMsg string
+	}{
+		"sameVersion": {
+			metadata: map[string]string{annotationSigningProfileVersion: profileVersionArn},
+		},
+		"differentVersion": {
+			metadata: map[string]string{annotationSigningProfileVersion: "arn:aws:signer:us-west-2:780792624090:/signing-profiles/NotationProfile/def456"},
+			errorMsg: "signing profile version arn:aws:signer:us-west-2:780792624090:/signing-profiles/NotationProfile/abc123 was requested but AWS Signer signed with signing profile version \"arn:aws:signer:us-west-2:780792624090:/signing-profiles/NotationProfile/def456\".",
+		},
+		"missingVersion": {
+			metadata: testSigMetadata,
+			errorMsg: "signing profile version arn:aws:signer:us-west-2:780792624090:/signing-profiles/NotationProfile/abc123 was requested but AWS Signer signed with signing profile version \"\".",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			mockSignerClient := client.NewMockInterface(mockCtrl)
+			mockSignerClient.EXPECT().SignPayload(gomock.Any(), gomock.Any()).DoAndReturn(
+				func(ctx context.Context, input *signer.SignPayloadInput, optFns ...func(*signer.Options)) (*signer.SignPayloadOutput, error) {
+					assert.Equal(t, testProfile, *input.ProfileName, "ProfileName mismatch")
+					assert.Equal(t, "780792624090", *input.ProfileOwner, "ProfileOwner mismatch")
+					return &signer.SignPayloadOutput{Signature: testSig, Metadata: test.metadata}, nil
+				})
+
+			req := mockGenerateEnvReq()
+			req.KeyID = profileVersionArn
+			response, err := New(mockSignerClient).GenerateEnvelope(context.TODO(), req)
+			if test.errorMsg != "" {
+				plgErr := toPluginError(err, t)
+				assert.Equal(t, plugin.ErrorCodeValidation, plgErr.ErrCode, "error code mismatch")
+				assert.Equal(t, test.errorMsg, plgErr.Message, "error message mismatch")
+				return
+			}
+			assert.NoError(t, err, "GenerateEnvelope() returned error")
+			assert.Equal(t, test.metadata, response.Annotations, "metadata mismatch")
+		})
+	}
+}
+
+func TestGenerateEnvelope_Expiry(t *testing.T) {
+	now := time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)
+
+	const day = 24 * 60 * 60
+	tests := map[string]struct {
+		expiry         uint64
+		validityPeriod *types.SignatureValidityPeriod
+		annotation     string
+		errorMsg       string
+	}{
+		"sameAsValidityDays": {
+			expiry:         30 * day,
+			validityPeriod: &types.SignatureValidityPeriod{Type: types.ValidityTypeDays, Value: 30},
+			annotation:     "2024-03-01T00:00:00Z",
+		},
+		"longerThanValidityMonths": {
+			expiry:         365 * day,
+			validityPeriod: &types.SignatureValidityPeriod{Type: types.ValidityTypeMonths, Value: 1},
+			annotation:     "2024-03-02T00:00:00Z",
+		},
+		"sameAsValidityYears": {
+			expiry:         366 * day,
+			validityPeriod: &types.SignatureValidityPeriod{Type: types.ValidityTypeYears, Value: 1},
+			annotation:     "2025-01-31T00:00:00Z",
+		},
+		"defaultValidity": {
+			expiry:     math.MaxUint64,
+			annotation: "2035-05-01T00:00:00Z",
+		},
+		"shorterThanValidity": {
+			expiry:         day,
+			validityPeriod: &types.SignatureValidityPeriod{Type: types.ValidityTypeYears, Value: 1},
+			errorMsg:       "requested signature expiry of 86400 seconds is shorter than the signature validity period of 1 years (31622400 seconds) of signing profile arn:aws:signer:us-west-2:780792624090:/signing-profiles/NotationProfile. Please use a signing profile with shorter signature validity period or increase the expiry.",
+		},
+		"unsupportedValidityType": {
+			expiry:         day,
+			validityPeriod: &types.SignatureValidityPeriod{Type: "WEEKS", Value: 1},
+			errorMsg:       "signing profile arn:aws:signer:us-west-2:780792624090:/signing-profiles/NotationProfile uses unsupported signature validity period type \"WEEKS\".",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			mockSignerClient := client.NewMockInterface(mockCtrl)
+			mockSignerClient.EXPECT().GetSigningProfile(gomock.Any(), gomock.Any()).DoAndReturn(
+				func(ctx context.Context, input *signer.GetSigningProfileInput, optFns ...func(*signer.Options)) (*signer.GetSigningProfileOutput, error) {
+					assert.Equal(t, testProfile, *input.ProfileName, "ProfileName mismatch")
+					assert.Equal(t, "780792624090", *input.ProfileOwner, "ProfileOwner mismatch")
+					return &signer.GetSigningProfileOutput{SignatureValidityPeriod: test.validityPeriod}, nil
+				})
+			if test.errorMsg == "" {
+				mockSignerClient.EXPECT().SignPayload(gomock.Any(), gomock.Any()).Return(&signer.SignPayloadOutput{Signature: testSig, Metadata: testSigMetadata}, nil)
+			}
+
+			req := mockGenerateEnvReq()
+			req.ExpiryDurationInSeconds = test.expiry
+			response, err := New(mockSignerClient, WithClock(func() time.Time { return now })).GenerateEnvelope(context.TODO(), req)
+			if test.errorMsg != "" {
+				plgErr := toPluginError(err, t)
+				assert.Equal(t, test.errorMsg, plgErr.Message, "error message mismatch")
+				return
+			}
+			assert.NoError(t, err, "GenerateEnvelope() returned error")
+			assert.Equal(t, map[string]string{"metadatakey": "metadatavalue", annotationSignatureExpiry: test.annotation}, response.Annotations, "annotations mismatch")
+			assert.Equal(t, map[string]string{"metadatakey": "metadatavalue"}, testSigMetadata, "SignPayload metadata modified")
+		})
+	}
+}
+
 func TestGenerateEnvelope_MalformedRequest(t *testing.T) {
 	badEnvTypeReq := mockGenerateEnvReq()
 	badEnvTypeReq.SignatureEnvelopeType = "badType"
 
+	coseEnvTypeReq := mockGenerateEnvReq()
+	coseEnvTypeReq.SignatureEnvelopeType = "application/cose"
+
 	badContractVersionReq := mockGenerateEnvReq()
 	badContractVersionReq.ContractVersion = "2.0"
 
@@ -75,8 +228,14 @@ func TestGenerateEnvelope_MalformedRequest(t *testing.T) {
 	invalidSigningProfileArnReq := mockGenerateEnvReq()
 	invalidSigningProfileArnReq.KeyID = "arn:aws:signer:us-west-2:123:/signing-profiles/name/version/invalid"
 
-	nonNilExpiryReq := mockGenerateEnvReq()
-	nonNilExpiryReq.ExpiryDurationInSeconds = 4
+	emptyProfileVersionReq := mockGenerateEnvReq()
+	emptyProfileVersionReq.KeyID = "arn:aws:signer:us-west-2:123:/signing-profiles/name/"
+
+	partitionRegionMismatchReq := mockGenerateEnvReq()
+	partitionRegionMismatchReq.KeyID = "arn:aws:signer:cn-north-1:123:/signing-profiles/name"
+
+	unknownPartitionReq := mockGenerateEnvReq()
+	unknownPartitionReq.KeyID = "arn:aws-mars:signer:mars-1:123:/signing-profiles/name"
 
 	tests := map[string]struct {
 		req      *plugin.GenerateEnvelopeRequest
@@ -86,6 +245,10 @@ func TestGenerateEnvelope_MalformedRequest(t *testing.T) {
 			req:      badEnvTypeReq,
 			errorMsg: "envelope type \"badType\" is not supported",
 		},
+		"coseEnvelopeTypeReq": {
+			req:      coseEnvTypeReq,
+			errorMsg: "envelope type \"application/cose\" is not supported",
+		},
 		"badContractVersionReq": {
 			req:      badContractVersionReq,
 			errorMsg: "\"2.0\" is not a supported notary plugin contract version",
@@ -98,9 +261,17 @@ func TestGenerateEnvelope_MalformedRequest(t *testing.T) {
 			req:      invalidSigningProfileArnReq,
 			errorMsg: fmt.Sprintf(errorMsgMalformedSigningProfileFmt, "arn:aws:signer:us-west-2:123:/signing-profiles/name/version/invalid"),
 		},
-		"nonNilExpiryReq": {
-			req:      nonNilExpiryReq,
-			errorMsg: "AWSSigner plugin doesn't support -e (--expiry) argument. Please use signing profile to set signature expiry.",
+		"emptyProfileVersionReq": {
+			req:      emptyProfileVersionReq,
+			errorMsg: fmt.Sprintf(errorMsgMalformedSigningProfileFmt, "arn:aws:signer:us-west-2:123:/signing-profiles/name/"),
+		},
+		"partitionRegionMismatchReq": {
+			req:      partitionRegionMismatchReq,
+			errorMsg: "arn:aws:signer:cn-north-1:123:/signing-profiles/name is not a valid AWS Signer signing profile ARN: region \"cn-north-1\" belongs to partition \"aws-cn\", not \"aws\".",
+		},
+		"unknownPartitionReq": {
+			req:      unknownPartitionReq,
+			errorMsg: "arn:aws-mars:signer:mars-1:123:/signing-profiles/name is not a valid AWS Signer signing profile ARN: partition \"aws-mars\" is not supported.",
 		},
 	}
 	for name, test := range tests {
@@ -181,6 +352,151 @@ func TestGenerateEnvelope_AWSSignerError(t *testing.T) {
 	}
 }
 
+func TestGenerateEnvelope_Spans(t *testing.T) {
+	request := mockGenerateEnvReq()
+	tests := map[string]struct {
+		err      error
+		outcomes map[string]string
+	}{
+		"success": {
+			outcomes: map[string]string{
+				"ValidateRequest":        tracing.OutcomeSuccess,
+				"ParseSigningProfileArn": tracing.OutcomeSuccess,
+				"Signer.SignPayload":     tracing.OutcomeSuccess,
+				"GenerateEnvelope":       tracing.OutcomeSuccess,
+			},
+		},
+		"signPayloadError": {
+			err: &smithy.GenericAPIError{Code: "AccessDeniedException", Message: "aws error message"},
+			outcomes: map[string]string{
+				"ValidateRequest":        tracing.OutcomeSuccess,
+				"ParseSigningProfileArn": tracing.OutcomeSuccess,
+				"Signer.SignPayload":     tracing.OutcomeFailure,
+				"GenerateEnvelope":       tracing.OutcomeFailure,
+			},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			recorder := setupSpanRecorder(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			mockSignerClient := client.NewMockInterface(mockCtrl)
+			mockSignerClient.EXPECT().SignPayload(gomock.Any(), gomock.Any()).DoAndReturn(
+				func(ctx context.Context, input *signer.SignPayloadInput, optFns ...func(*signer.Options)) (*signer.SignPayloadOutput, error) {
+					assert.True(t, trace.SpanFromContext(ctx).IsRecording(), "SignPayload called without span")
+					if tc.err != nil {
+						return nil, tc.err
+					}
+					return &signer.SignPayloadOutput{Signature: testSig}, nil
+				})
+
+			_, _ = New(mockSignerClient).GenerateEnvelope(context.TODO(), request)
+
+			spans := recorder.Ended()
+			outcomes := map[string]string{}
+			for _, span := range spans {
+				for _, attr := range span.Attributes() {
+					if attr.Key == tracing.AttrOutcome {
+						outcomes[span.Name()] = attr.Value.AsString()
+					}
+				}
+				if span.Name() == "Signer.SignPayload" || span.Name() == "GenerateEnvelope" {
+					assert.Contains(t, span.Attributes(), tracing.AttrProfileArn.String(request.KeyID), "profile ARN attribute mismatch")
+					assert.Contains(t, span.Attributes(), tracing.AttrRegion.String("us-west-2"), "region attribute mismatch")
+				}
+			}
+			assert.Equal(t, tc.outcomes, outcomes, "span outcomes mismatch")
+		})
+	}
+}
+
+func TestDescribeKey(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	mockSignerClient := client.NewMockInterface(mockCtrl)
+
+	mockSignerClient.EXPECT().GetSigningProfile(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, input *signer.GetSigningProfileInput, optFns ...func(*signer.Options)) (*signer.GetSigningProfileOutput, error) {
+			assert.Equal(t, testProfile, *input.ProfileName, "ProfileName mismatch")
+			assert.Equal(t, "780792624090", *input.ProfileOwner, "ProfileOwner mismatch")
+			return &signer.GetSigningProfileOutput{PlatformId: aws.String(platformNotation)}, nil
+		})
+
+	req := mockDescribeKeyReq()
+	response, err := New(mockSignerClient).DescribeKey(context.TODO(), req)
+	assert.NoError(t, err, "DescribeKey() returned error")
+	assert.Equal(t, req.KeyID, response.KeyID, "KeyID mismatch")
+	assert.Equal(t, plugin.KeySpecEC384, response.KeySpec, "KeySpec mismatch")
+}
+
+func TestDescribeKey_ProfileVersion(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	mockSignerClient := client.NewMockInterface(mockCtrl)
+
+	mockSignerClient.EXPECT().GetSigningProfile(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, input *signer.GetSigningProfileInput, optFns ...func(*signer.Options)) (*signer.GetSigningProfileOutput, error) {
+			assert.Equal(t, testProfile, *input.ProfileName, "ProfileName mismatch")
+			return &signer.GetSigningProfileOutput{PlatformId: aws.String(platformNotation)}, nil
+		})
+
+	req := mockDescribeKeyReq()
+	req.KeyID = "arn:aws:signer:us-west-2:780792624090:/signing-profiles/NotationProfile/abc123"
+	response, err := New(mockSignerClient).DescribeKey(context.TODO(), req)
+	assert.NoError(t, err, "DescribeKey() returned error")
+	assert.Equal(t, req.KeyID, response.KeyID, "KeyID mismatch")
+}
+
+func TestDescribeKey_Error(t *testing.T) {
+	badContractVersionReq := mockDescribeKeyReq()
+	badContractVersionReq.ContractVersion = "2.0"
+
+	badProfileArnReq := mockDescribeKeyReq()
+	badProfileArnReq.KeyID = "NotationProfile"
+
+	tests := map[string]struct {
+		req       *plugin.DescribeKeyRequest
+		sigOutput *signer.GetSigningProfileOutput
+		sigErr    error
+		errorMsg  string
+	}{
+		"badContractVersionReq": {
+			req:      badContractVersionReq,
+			errorMsg: "\"2.0\" is not a supported notary plugin contract version",
+		},
+		"badProfileArnReq": {
+			req:      badProfileArnReq,
+			errorMsg: fmt.Sprintf(errorMsgMalformedSigningProfileFmt, "NotationProfile"),
+		},
+		"unsupportedPlatform": {
+			req:       mockDescribeKeyReq(),
+			sigOutput: &signer.GetSigningProfileOutput{PlatformId: aws.String("AWSLambda-SHA384-ECDSA")},
+			errorMsg:  fmt.Sprintf(errorMsgUnsupportedPlatformFmt, mockDescribeKeyReq().KeyID, "AWSLambda-SHA384-ECDSA"),
+		},
+		"AccessDeniedException": {
+			req: mockDescribeKeyReq(),
+			sigErr: &smithy.GenericAPIError{
+				Code:    "AccessDeniedException",
+				Message: "aws error message",
+			},
+			errorMsg: "Failed to call AWSSigner. Error: aws error message.",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			mockSignerClient := client.NewMockInterface(mockCtrl)
+			mockSignerClient.EXPECT().GetSigningProfile(gomock.Any(), gomock.Any()).Return(test.sigOutput, test.sigErr).AnyTimes()
+
+			_, err := New(mockSignerClient).DescribeKey(context.TODO(), test.req)
+			plgErr := toPluginError(err, t)
+			assert.Equal(t, test.errorMsg, plgErr.Message, "error message mismatch")
+		})
+	}
+}
+
 func toPluginError(err error, t *testing.T) *plugin.Error {
 	if err == nil {
 		t.Error("expected error but not found")
@@ -202,6 +518,22 @@ func mockGenerateEnvReq() *plugin.GenerateEnvelopeRequest {
 	}
 }
 
+func mockDescribeKeyReq() *plugin.DescribeKeyRequest {
+	return &plugin.DescribeKeyRequest{
+		ContractVersion: plugin.ContractVersion,
+		KeyID:           "arn:aws:signer:us-west-2:780792624090:/signing-profiles/NotationProfile",
+	}
+}
+
+func setupSpanRecorder(t *testing.T) *tracetest.SpanRecorder {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() {
+		otel.SetTracerProvider(noop.NewTracerProvider())
+	})
+	return recorder
+}
+
 func getMockErrorClient(err error, t *testing.T) (client.Interface, *gomock.Controller) {
 	mockCtrl := gomock.NewController(t)
 	mockSignerClient := client.NewMockInterface(mockCtrl)
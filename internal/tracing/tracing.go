@@ -0,0 +1,77 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// Package tracing provides OpenTelemetry spans for signing and verification. Spans are created using the global
+// tracer provider, so they are recorded only when the caller has set one through otel.SetTracerProvider and are
+// no-op otherwise.
+package tracing
+
+import (
+	"context"
+	"errors"
+
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/notaryproject/notation-plugin-framework-go/plugin"
+)
+
+const tracerName = "github.com/aws/aws-signer-notation-plugin"
+
+// Span attributes.
+const (
+	AttrProfileArn = attribute.Key("aws.signer.profile_arn")
+	AttrRegion     = attribute.Key("cloud.region")
+	AttrOutcome    = attribute.Key("aws.signer.outcome")
+)
+
+// Values of AttrOutcome.
+const (
+	OutcomeSuccess = "success"
+	OutcomeFailure = "failure"
+)
+
+// Start creates a span with the given name and attributes as a child of the span in ctx, if any.
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// ProfileAttributes returns the span attributes of the given signing profile ARN or signing profile version ARN.
+func ProfileAttributes(profileArn string) []attribute.KeyValue {
+	attrs := []attribute.KeyValue{AttrProfileArn.String(profileArn)}
+	if parsedArn, err := arn.Parse(profileArn); err == nil {
+		attrs = append(attrs, AttrRegion.String(parsedArn.Region))
+	}
+	return attrs
+}
+
+// End records the outcome of the operation traced by span, along with err if the operation failed, and ends span.
+func End(span trace.Span, err error) {
+	if err == nil {
+		span.SetAttributes(AttrOutcome.String(OutcomeSuccess))
+		span.End()
+		return
+	}
+	span.SetAttributes(AttrOutcome.String(OutcomeFailure))
+	span.RecordError(err)
+	var plgErr *plugin.Error
+	if errors.As(err, &plgErr) {
+		span.SetStatus(codes.Error, plgErr.Message)
+	} else {
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -0,0 +1,113 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package tracing
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/notaryproject/notation-plugin-framework-go/plugin"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+func TestStartEnd(t *testing.T) {
+	tests := map[string]struct {
+		err         error
+		outcome     string
+		statusCode  codes.Code
+		description string
+	}{
+		"success": {
+			outcome: OutcomeSuccess,
+		},
+		"pluginError": {
+			err:         plugin.NewValidationError("invalid request"),
+			outcome:     OutcomeFailure,
+			statusCode:  codes.Error,
+			description: "invalid request",
+		},
+		"error": {
+			err:         errors.New("connection refused"),
+			outcome:     OutcomeFailure,
+			statusCode:  codes.Error,
+			description: "connection refused",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			recorder := setupSpanRecorder(t)
+			ctx, parent := Start(context.TODO(), "Parent")
+			_, span := Start(ctx, "Child", AttrRegion.String("us-west-2"))
+			End(span, tc.err)
+			parent.End()
+
+			spans := recorder.Ended()
+			if assert.Len(t, spans, 2, "span count mismatch") {
+				child := spans[0]
+				assert.Equal(t, "Child", child.Name(), "span name mismatch")
+				assert.Equal(t, spans[1].SpanContext().SpanID(), child.Parent().SpanID(), "parent span mismatch")
+				assert.Contains(t, child.Attributes(), AttrRegion.String("us-west-2"), "region attribute mismatch")
+				assert.Contains(t, child.Attributes(), AttrOutcome.String(tc.outcome), "outcome attribute mismatch")
+				assert.Equal(t, tc.statusCode, child.Status().Code, "status code mismatch")
+				assert.Equal(t, tc.description, child.Status().Description, "status description mismatch")
+			}
+		})
+	}
+}
+
+func TestStart_NoTracerProvider(t *testing.T) {
+	_, span := Start(context.TODO(), "Span")
+	defer End(span, nil)
+	assert.False(t, span.IsRecording(), "span recorded without tracer provider")
+}
+
+func TestProfileAttributes(t *testing.T) {
+	tests := map[string]struct {
+		profileArn string
+		expected   []attribute.KeyValue
+	}{
+		"profileArn": {
+			profileArn: "arn:aws:signer:us-west-2:123456789012:/signing-profiles/NotationProfile",
+			expected: []attribute.KeyValue{
+				AttrProfileArn.String("arn:aws:signer:us-west-2:123456789012:/signing-profiles/NotationProfile"),
+				AttrRegion.String("us-west-2"),
+			},
+		},
+		"malformedArn": {
+			profileArn: "NotationProfile",
+			expected:   []attribute.KeyValue{AttrProfileArn.String("NotationProfile")},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, ProfileAttributes(tc.profileArn), "attributes mismatch")
+		})
+	}
+}
+
+func setupSpanRecorder(t *testing.T) *tracetest.SpanRecorder {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() {
+		otel.SetTracerProvider(noop.NewTracerProvider())
+	})
+	return recorder
+}
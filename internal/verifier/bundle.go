@@ -0,0 +1,149 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package verifier
+
+import (
+	"crypto/ecdsa"
+	"crypto/sha512"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+const defaultRevocationBundleMaxAge = 7 * 24 * time.Hour
+
+// revocationBundle is an exported AWS Signer revocation snapshot along with its signature. Signature is the
+// ASN.1 encoded ECDSA signature of SHA-384 digest of Snapshot.
+type revocationBundle struct {
+	Snapshot  []byte `json:"snapshot"`
+	Signature []byte `json:"signature"`
+}
+
+// revocationSnapshot lists the entities revoked as of GeneratedAt.
+type revocationSnapshot struct {
+	GeneratedAt              time.Time               `json:"generatedAt"`
+	RevokedProfileVersions   []revokedProfileVersion `json:"revokedProfileVersions"`
+	RevokedJobs              []string                `json:"revokedJobs"`
+	RevokedCertificateHashes []string                `json:"revokedCertificateHashes"`
+}
+
+// revokedProfileVersion is a revoked signing profile version. Signatures generated on or after EffectiveFrom
+// are revoked, zero EffectiveFrom revokes all signatures.
+type revokedProfileVersion struct {
+	Arn           string    `json:"arn"`
+	EffectiveFrom time.Time `json:"effectiveFrom"`
+}
+
+// loadRevocationSnapshot reads the revocation bundle at bundlePath, verifies its signature using the PEM encoded
+// public key at publicKeyPath and rejects snapshots older than maxAge or generated later than now plus the default
+// clock skew tolerance, since such snapshots would never expire.
+func loadRevocationSnapshot(bundlePath, publicKeyPath string, maxAge time.Duration, now time.Time) (*revocationSnapshot, error) {
+	publicKey, err := readECDSAPublicKey(publicKeyPath)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read revocation bundle public key: %w", err)
+	}
+
+	b, err := os.ReadFile(bundlePath)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read revocation bundle: %w", err)
+	}
+	var bundle revocationBundle
+	if err := json.Unmarshal(b, &bundle); err != nil {
+		return nil, fmt.Errorf("unable to parse revocation bundle: %w", err)
+	}
+
+	digest := sha512.Sum384(bundle.Snapshot)
+	if !ecdsa.VerifyASN1(publicKey, digest[:], bundle.Signature) {
+		return nil, errors.New("revocation bundle signature verification failed")
+	}
+
+	var snapshot revocationSnapshot
+	if err := json.Unmarshal(bundle.Snapshot, &snapshot); err != nil {
+		return nil, fmt.Errorf("unable to parse revocation snapshot: %w", err)
+	}
+	if snapshot.GeneratedAt.IsZero() {
+		return nil, errors.New("revocation snapshot is missing generatedAt")
+	}
+	if snapshot.GeneratedAt.After(now.Add(defaultClockSkew)) {
+		return nil, fmt.Errorf("revocation snapshot generated at %s is later than current time %s", formatTime(snapshot.GeneratedAt), formatTime(now))
+	}
+	if age := now.Sub(snapshot.GeneratedAt); age > maxAge {
+		return nil, fmt.Errorf("revocation snapshot generated at %s is older than maximum age %s", snapshot.GeneratedAt.Format(time.RFC3339), maxAge)
+	}
+
+	return &snapshot, nil
+}
+
+func readECDSAPublicKey(path string) (*ecdsa.PublicKey, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(b)
+	if block == nil {
+		return nil, errors.New("no PEM data found")
+	}
+
+	var key any
+	switch block.Type {
+	case "CERTIFICATE":
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		key = cert.PublicKey
+	default:
+		if key, err = x509.ParsePKIXPublicKey(block.Bytes); err != nil {
+			return nil, err
+		}
+	}
+
+	ecdsaKey, ok := key.(*ecdsa.PublicKey)
+	if !ok {
+		return nil, errors.New("public key is not an ECDSA key")
+	}
+	return ecdsaKey, nil
+}
+
+// revokedEntities returns the entities revoked for given profile version, signing job and certificate hashes, in
+// the same form as returned by GetRevocationStatus API.
+func (s *revocationSnapshot) revokedEntities(profileVersionArn, jobArn string, certHashes []string, signingTime time.Time) []string {
+	var revoked []string
+	for _, pv := range s.RevokedProfileVersions {
+		if strings.EqualFold(pv.Arn, profileVersionArn) && !signingTime.Before(pv.EffectiveFrom) {
+			revoked = append(revoked, profileVersionArn)
+			break
+		}
+	}
+	for _, job := range s.RevokedJobs {
+		if strings.EqualFold(job, jobArn) {
+			revoked = append(revoked, jobArn)
+			break
+		}
+	}
+	for _, certHash := range certHashes {
+		for _, revokedHash := range s.RevokedCertificateHashes {
+			if strings.EqualFold(revokedHash, certHash) {
+				revoked = append(revoked, certHash)
+				break
+			}
+		}
+	}
+	return revoked
+}
@@ -0,0 +1,227 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package verifier
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha512"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/notaryproject/notation-plugin-framework-go/plugin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/aws/aws-signer-notation-plugin/internal/client"
+)
+
+func TestVerify_RevocationBundle(t *testing.T) {
+	signingTime, _ := time.Parse(time.RFC3339, "2022-07-06T19:10:28+00:00")
+	tests := map[string]struct {
+		snapshot revocationSnapshot
+		success  bool
+		reason   string
+	}{
+		"notRevoked": {
+			snapshot: revocationSnapshot{RevokedJobs: []string{"arn:aws:signer:us-west-2:000000000000:/signing-jobs/other"}},
+			success:  true,
+			reason:   reasonNotRevoked,
+		},
+		"revokedJob": {
+			snapshot: revocationSnapshot{RevokedJobs: []string{testJobArn}},
+			reason:   fmt.Sprintf(reasonRevokedResourceFmt, testJobArn),
+		},
+		"revokedProfileVersion": {
+			snapshot: revocationSnapshot{RevokedProfileVersions: []revokedProfileVersion{{Arn: testProfileVersionArn, EffectiveFrom: signingTime}}},
+			reason:   fmt.Sprintf(reasonRevokedResourceFmt, testProfileVersionArn),
+		},
+		"profileVersionRevokedAfterSigning": {
+			snapshot: revocationSnapshot{RevokedProfileVersions: []revokedProfileVersion{{Arn: testProfileVersionArn, EffectiveFrom: signingTime.Add(time.Hour)}}},
+			success:  true,
+			reason:   reasonNotRevoked,
+		},
+		"revokedCertificate": {
+			snapshot: revocationSnapshot{RevokedCertificateHashes: []string{testCertificate2Hash + testCertificate2Hash}},
+			reason:   reasonRevokedCertificate,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			test.snapshot.GeneratedAt = time.Now()
+			bundlePath, publicKeyPath := writeTestBundle(t, test.snapshot, nil)
+
+			// AWS Signer must not be called for offline revocation check
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			request := mockVerifySigRequest()
+			request.PluginConfig = map[string]string{
+				configKeyRevocationBundle:          bundlePath,
+				configKeyRevocationBundlePublicKey: publicKeyPath,
+			}
+			actualResponse, err := New(client.NewMockInterface(mockCtrl)).Verify(context.TODO(), request)
+			expectedResponse := getVerifySigResponse(true, testTISuccessReason, test.success, test.reason)
+			validateResponse(t, expectedResponse, *actualResponse, err)
+		})
+	}
+}
+
+func TestVerify_RevocationBundleError(t *testing.T) {
+	bundlePath, publicKeyPath := writeTestBundle(t, revocationSnapshot{GeneratedAt: time.Now().Add(-48 * time.Hour)}, nil)
+	for _, mode := range revocationFailureModes {
+		t.Run(mode, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			request := mockVerifySigRequest()
+			request.PluginConfig = map[string]string{
+				configKeyRevocationBundle:          bundlePath,
+				configKeyRevocationBundlePublicKey: publicKeyPath,
+				configKeyRevocationBundleMaxAge:    "24h",
+				configKeyRevocationFailureMode:     mode,
+			}
+			_, err := New(client.NewMockInterface(mockCtrl)).Verify(context.TODO(), request)
+			if assert.Error(t, err, "Verify() passed with stale revocation bundle") {
+				pluginErr := toPluginError(err, t)
+				assert.Equal(t, plugin.ErrorCodeValidation, pluginErr.ErrCode, "error code mismatch")
+				assert.Contains(t, pluginErr.Message, "is older than maximum age 24h0m0s")
+			}
+		})
+	}
+}
+
+func TestLoadRevocationSnapshot_Error(t *testing.T) {
+	now := time.Now()
+	bundlePath, publicKeyPath := writeTestBundle(t, revocationSnapshot{GeneratedAt: now}, nil)
+	_, otherPublicKeyPath := writeTestBundle(t, revocationSnapshot{GeneratedAt: now}, nil)
+	tamperedBundlePath, _ := writeTestBundle(t, revocationSnapshot{GeneratedAt: now}, func(b *revocationBundle) {
+		b.Snapshot = []byte(`{"generatedAt":"` + now.Format(time.RFC3339) + `"}`)
+	})
+	noTimeBundlePath, noTimePublicKeyPath := writeTestBundle(t, revocationSnapshot{}, nil)
+	futureBundlePath, futurePublicKeyPath := writeTestBundle(t, revocationSnapshot{GeneratedAt: now.Add(time.Hour)}, nil)
+	notJSONPath := filepath.Join(t.TempDir(), "bundle.json")
+	_ = os.WriteFile(notJSONPath, []byte("not json"), 0600)
+
+	tests := map[string]struct {
+		bundlePath    string
+		publicKeyPath string
+		errorMsg      string
+	}{
+		"missingBundle": {
+			bundlePath:    filepath.Join(t.TempDir(), "missing.json"),
+			publicKeyPath: publicKeyPath,
+			errorMsg:      "unable to read revocation bundle",
+		},
+		"missingPublicKey": {
+			bundlePath:    bundlePath,
+			publicKeyPath: filepath.Join(t.TempDir(), "missing.pem"),
+			errorMsg:      "unable to read revocation bundle public key",
+		},
+		"invalidPublicKey": {
+			bundlePath:    bundlePath,
+			publicKeyPath: notJSONPath,
+			errorMsg:      "no PEM data found",
+		},
+		"malformedBundle": {
+			bundlePath:    notJSONPath,
+			publicKeyPath: publicKeyPath,
+			errorMsg:      "unable to parse revocation bundle",
+		},
+		"wrongPublicKey": {
+			bundlePath:    bundlePath,
+			publicKeyPath: otherPublicKeyPath,
+			errorMsg:      "revocation bundle signature verification failed",
+		},
+		"tamperedSnapshot": {
+			bundlePath:    tamperedBundlePath,
+			publicKeyPath: publicKeyPath,
+			errorMsg:      "revocation bundle signature verification failed",
+		},
+		"missingGeneratedAt": {
+			bundlePath:    noTimeBundlePath,
+			publicKeyPath: noTimePublicKeyPath,
+			errorMsg:      "revocation snapshot is missing generatedAt",
+		},
+		"futureGeneratedAt": {
+			bundlePath:    futureBundlePath,
+			publicKeyPath: futurePublicKeyPath,
+			errorMsg:      "is later than current time",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := loadRevocationSnapshot(test.bundlePath, test.publicKeyPath, time.Hour, now)
+			if assert.Error(t, err, "loadRevocationSnapshot() expected error but not found") {
+				assert.Contains(t, err.Error(), test.errorMsg)
+			}
+		})
+	}
+}
+
+func TestLoadRevocationSnapshot_Certificate(t *testing.T) {
+	bundlePath, publicKeyPath := writeTestBundle(t, revocationSnapshot{GeneratedAt: time.Now()}, nil)
+	keyBlock, _ := pem.Decode(readFile(t, publicKeyPath))
+	key, _ := x509.ParsePKIXPublicKey(keyBlock.Bytes)
+	signerKey, _ := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	template := &x509.Certificate{SerialNumber: big.NewInt(1), NotBefore: time.Now(), NotAfter: time.Now().Add(time.Hour)}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key, signerKey)
+	if err != nil {
+		t.Fatalf("unable to create certificate: %v", err)
+	}
+	certPath := filepath.Join(t.TempDir(), "cert.pem")
+	_ = os.WriteFile(certPath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600)
+
+	_, err = loadRevocationSnapshot(bundlePath, certPath, time.Hour, time.Now())
+	assert.NoError(t, err, "loadRevocationSnapshot() returned error")
+}
+
+// writeTestBundle signs snapshot with a new ECDSA key and returns paths of the bundle and the public key.
+func writeTestBundle(t *testing.T, snapshot revocationSnapshot, modify func(*revocationBundle)) (string, string) {
+	dir := t.TempDir()
+	key, _ := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	snapshotBytes, _ := json.Marshal(snapshot)
+	digest := sha512.Sum384(snapshotBytes)
+	sig, _ := ecdsa.SignASN1(rand.Reader, key, digest[:])
+	bundle := revocationBundle{Snapshot: snapshotBytes, Signature: sig}
+	if modify != nil {
+		modify(&bundle)
+	}
+
+	bundleBytes, _ := json.Marshal(bundle)
+	bundlePath := filepath.Join(dir, "bundle.json")
+	_ = os.WriteFile(bundlePath, bundleBytes, 0600)
+
+	publicKeyBytes, _ := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	publicKeyPath := filepath.Join(dir, "public.pem")
+	_ = os.WriteFile(publicKeyPath, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicKeyBytes}), 0600)
+	return bundlePath, publicKeyPath
+}
+
+func readFile(t *testing.T, path string) []byte {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("unable to read %s: %v", path, err)
+	}
+	return b
+}
@@ -0,0 +1,118 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package verifier
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
+)
+
+const (
+	patternWildcard       = "*"
+	signingProfilesPrefix = "/signing-profiles/"
+)
+
+// identityPattern is a trusted identity which matches multiple signing profiles. The grammar is
+//
+//	arn:<partition>:signer:<region>:<account-id>:/signing-profiles/<profile-name>[/<profile-version>]
+//
+// where <region> may be "*" to match any region and <profile-name> may end with "*" to match every profile name
+// starting with the given prefix. Wildcards aren't supported anywhere else, so a pattern is always scoped to
+// a partition and an account.
+type identityPattern struct {
+	partition      string
+	region         string // empty if any region
+	accountID      string
+	profileName    string
+	isPrefix       bool   // true if profileName is a prefix
+	profileVersion string // empty if any version
+}
+
+func isIdentityPattern(identity string) bool {
+	return strings.Contains(identity, patternWildcard)
+}
+
+// parseIdentityPattern parses trusted identity containing wildcard as per identityPattern grammar.
+func parseIdentityPattern(identity string) (*identityPattern, error) {
+	a, err := arn.Parse(identity)
+	if err != nil {
+		return nil, err
+	}
+	if isIdentityPattern(a.Partition) || a.Service != "signer" {
+		return nil, errors.New("partition must not contain wildcard and service must be \"signer\"")
+	}
+	if a.AccountID == "" || isIdentityPattern(a.AccountID) {
+		return nil, errors.New("account ID is required and must not contain wildcard")
+	}
+	if isIdentityPattern(a.Region) && a.Region != patternWildcard {
+		return nil, errors.New("region must either be \"*\" or not contain wildcard")
+	}
+
+	parts := strings.Split(strings.TrimPrefix(a.Resource, signingProfilesPrefix), "/")
+	if !strings.HasPrefix(a.Resource, signingProfilesPrefix) || len(parts) > 2 {
+		return nil, errors.New("resource must be a signing profile or signing profile version")
+	}
+	name := parts[0]
+	if name == "" {
+		return nil, errors.New("profile name is missing")
+	}
+	if isIdentityPattern(strings.TrimSuffix(name, patternWildcard)) {
+		return nil, errors.New("wildcard is only supported at the end of profile name")
+	}
+
+	p := &identityPattern{
+		partition:   a.Partition,
+		accountID:   a.AccountID,
+		profileName: strings.TrimSuffix(name, patternWildcard),
+		isPrefix:    strings.HasSuffix(name, patternWildcard),
+	}
+	if a.Region != patternWildcard {
+		p.region = a.Region
+	}
+	if len(parts) == 2 {
+		if isIdentityPattern(parts[1]) {
+			return nil, errors.New("wildcard is not supported in profile version")
+		}
+		p.profileVersion = parts[1]
+	}
+	return p, nil
+}
+
+// matches returns true if given signing profile version ARN matches the pattern.
+func (p *identityPattern) matches(profileVersionArn string) bool {
+	a, ok := isSigningProfileArn(profileVersionArn)
+	if !ok {
+		return false
+	}
+	parts := strings.Split(strings.TrimPrefix(a.Resource, signingProfilesPrefix), "/")
+	if len(parts) != 2 {
+		return false
+	}
+	name, version := parts[0], parts[1]
+
+	return strings.EqualFold(a.Partition, p.partition) &&
+		(p.region == "" || strings.EqualFold(a.Region, p.region)) &&
+		a.AccountID == p.accountID &&
+		p.matchesProfileName(name) &&
+		(p.profileVersion == "" || strings.EqualFold(version, p.profileVersion))
+}
+
+func (p *identityPattern) matchesProfileName(name string) bool {
+	if p.isPrefix {
+		return strings.HasPrefix(strings.ToLower(name), strings.ToLower(p.profileName))
+	}
+	return strings.EqualFold(name, p.profileName)
+}
@@ -0,0 +1,169 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package verifier
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/signer"
+	"github.com/notaryproject/notation-plugin-framework-go/plugin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIdentityPattern_Matches(t *testing.T) {
+	tests := map[string]struct {
+		pattern string
+		match   bool
+	}{
+		"anyRegion":             {pattern: "arn:aws:signer:*:000000000000:/signing-profiles/NotaryPluginIntegProfile", match: true},
+		"profileNamePrefix":     {pattern: "arn:aws:signer:us-west-2:000000000000:/signing-profiles/NotaryPlugin*", match: true},
+		"profileNamePrefixCase": {pattern: "arn:aws:signer:us-west-2:000000000000:/signing-profiles/notaryplugin*", match: true},
+		"anyProfileName":        {pattern: "arn:aws:signer:*:000000000000:/signing-profiles/*", match: true},
+		"anyRegionWithVersion":  {pattern: "arn:aws:signer:*:000000000000:/signing-profiles/NotaryPluginIntegProfile/OF8IVUsPJq", match: true},
+		"prefixWithVersion":     {pattern: "arn:aws:signer:*:000000000000:/signing-profiles/Notary*/OF8IVUsPJq", match: true},
+		"differentAccount":      {pattern: "arn:aws:signer:*:111122223333:/signing-profiles/*"},
+		"differentRegion":       {pattern: "arn:aws:signer:us-east-1:000000000000:/signing-profiles/Notary*"},
+		"differentPartition":    {pattern: "arn:aws-cn:signer:*:000000000000:/signing-profiles/*"},
+		"differentPrefix":       {pattern: "arn:aws:signer:*:000000000000:/signing-profiles/team-*"},
+		"differentVersion":      {pattern: "arn:aws:signer:*:000000000000:/signing-profiles/Notary*/abc"},
+		"exactNameNotPrefix":    {pattern: "arn:aws:signer:*:000000000000:/signing-profiles/NotaryPlugin"},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			p, err := parseIdentityPattern(test.pattern)
+			if err != nil {
+				t.Fatalf("parseIdentityPattern() returned error: %v", err)
+			}
+			assert.Equal(t, test.match, p.matches(testProfileVersionArn))
+		})
+	}
+}
+
+func TestParseIdentityPattern_Error(t *testing.T) {
+	tests := map[string]string{
+		"wildcardPartition":       "arn:*:signer:*:000000000000:/signing-profiles/*",
+		"wildcardAccount":         "arn:aws:signer:us-west-2:*:/signing-profiles/*",
+		"missingAccount":          "arn:aws:signer:us-west-2::/signing-profiles/*",
+		"partialRegionWildcard":   "arn:aws:signer:us-*:000000000000:/signing-profiles/*",
+		"wildcardInMiddleOfName":  "arn:aws:signer:*:000000000000:/signing-profiles/team-*-prod",
+		"wildcardVersion":         "arn:aws:signer:*:000000000000:/signing-profiles/team/*",
+		"missingProfileName":      "arn:aws:signer:*:000000000000:/signing-profiles/",
+		"tooManyResourceSegments": "arn:aws:signer:*:000000000000:/signing-profiles/team/version/*",
+		"notArn":                  "team-*",
+	}
+	for name, pattern := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := parseIdentityPattern(pattern)
+			assert.Error(t, err, "parseIdentityPattern() expected error but not found")
+		})
+	}
+}
+
+func TestVerify_TrustedIdentityPattern(t *testing.T) {
+	pattern := "arn:aws:signer:*:000000000000:/signing-profiles/NotaryPlugin*"
+	mockSignerClient, mockCtrl := getMockClient(nil, &signer.GetRevocationStatusOutput{}, nil, t)
+	defer mockCtrl.Finish()
+
+	request := mockVerifySigRequest()
+	request.TrustPolicy.TrustedIdentities = []string{"arn:aws:signer:*:000000000000:/signing-profiles/team-*", pattern}
+	actualResponse, err := New(mockSignerClient).Verify(context.TODO(), request)
+
+	expectedResponse := getVerifySigResponse(true, fmt.Sprintf(reasonTrustedPatternSuccessFmt, testProfileVersionArn, pattern), true, reasonNotRevoked)
+	validateResponse(t, expectedResponse, *actualResponse, err)
+}
+
+func TestVerify_InvalidTrustedIdentityPattern(t *testing.T) {
+	pattern := "arn:aws:signer:*:*:/signing-profiles/*"
+	request := mockVerifySigRequest()
+	request.TrustPolicy.TrustedIdentities = []string{pattern}
+
+	_, err := New(nil).Verify(context.TODO(), request)
+	plgErr := toPluginError(err, t)
+	assert.Equal(t, plugin.ErrorCodeValidation, plgErr.ErrCode, "error code mismatch")
+	assert.Equal(t, "trusted identity \"arn:aws:signer:*:*:/signing-profiles/*\" is not a valid AWS Signer identity pattern: account ID is required and must not contain wildcard.", plgErr.Message)
+}
+
+func TestVerify_TrustedIdentityPartitions(t *testing.T) {
+	tests := map[string]struct {
+		profileVersionArn string
+		trustedIdentity   string
+	}{
+		"aws": {
+			profileVersionArn: "arn:aws:signer:us-west-2:000000000000:/signing-profiles/Profile/abc",
+			trustedIdentity:   "arn:aws:signer:us-west-2:000000000000:/signing-profiles/Profile",
+		},
+		"aws-cn": {
+			profileVersionArn: "arn:aws-cn:signer:cn-north-1:000000000000:/signing-profiles/Profile/abc",
+			trustedIdentity:   "arn:aws-cn:signer:cn-north-1:000000000000:/signing-profiles/Profile",
+		},
+		"aws-us-gov": {
+			profileVersionArn: "arn:aws-us-gov:signer:us-gov-west-1:000000000000:/signing-profiles/Profile/abc",
+			trustedIdentity:   "arn:aws-us-gov:signer:us-gov-west-1:000000000000:/signing-profiles/Profile",
+		},
+		"aws-iso": {
+			profileVersionArn: "arn:aws-iso:signer:us-iso-east-1:000000000000:/signing-profiles/Profile/abc",
+			trustedIdentity:   "arn:aws-iso:signer:us-iso-east-1:000000000000:/signing-profiles/Profile",
+		},
+		"aws-iso-b": {
+			profileVersionArn: "arn:aws-iso-b:signer:us-isob-east-1:000000000000:/signing-profiles/Profile/abc",
+			trustedIdentity:   "arn:aws-iso-b:signer:us-isob-east-1:000000000000:/signing-profiles/Profile",
+		},
+		"aws-iso-e": {
+			profileVersionArn: "arn:aws-iso-e:signer:eu-isoe-west-1:000000000000:/signing-profiles/Profile/abc",
+			trustedIdentity:   "arn:aws-iso-e:signer:eu-isoe-west-1:000000000000:/signing-profiles/Profile",
+		},
+		"aws-iso-f": {
+			profileVersionArn: "arn:aws-iso-f:signer:us-isof-south-1:000000000000:/signing-profiles/Profile/abc",
+			trustedIdentity:   "arn:aws-iso-f:signer:us-isof-south-1:000000000000:/signing-profiles/Profile",
+		},
+		"aws-cn-pattern": {
+			profileVersionArn: "arn:aws-cn:signer:cn-northwest-1:000000000000:/signing-profiles/Profile/abc",
+			trustedIdentity:   "arn:aws-cn:signer:*:000000000000:/signing-profiles/Pro*",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			request := mockVerifySigRequest()
+			request.Signature.CriticalAttributes.ExtendedAttributes[attrSigningProfileVersion] = test.profileVersionArn
+			request.TrustPolicy.TrustedIdentities = []string{test.trustedIdentity}
+			request.TrustPolicy.SignatureVerification = []plugin.Capability{plugin.CapabilityTrustedIdentityVerifier}
+
+			actualResponse, err := New(nil).Verify(context.TODO(), request)
+			assert.NoError(t, err, "Verify() returned error")
+			assert.True(t, actualResponse.VerificationResults[plugin.CapabilityTrustedIdentityVerifier].Success, "trusted identity verification failed")
+		})
+	}
+}
+
+func TestVerify_TrustedIdentityPartitionMismatch(t *testing.T) {
+	tests := map[string]string{
+		"chinaRegionInAWS":     "arn:aws:signer:cn-north-1:000000000000:/signing-profiles/Profile",
+		"govCloudRegionInAWS":  "arn:aws:signer:us-gov-west-1:000000000000:/signing-profiles/Profile",
+		"commercialRegionInCN": "arn:aws-cn:signer:us-west-2:000000000000:/signing-profiles/Profile",
+		"unknownPartition":     "arn:aws-mars:signer:*:000000000000:/signing-profiles/Profile*",
+	}
+	for name, identity := range tests {
+		t.Run(name, func(t *testing.T) {
+			request := mockVerifySigRequest()
+			request.TrustPolicy.TrustedIdentities = []string{identity}
+
+			_, err := New(nil).Verify(context.TODO(), request)
+			plgErr := toPluginError(err, t)
+			assert.Equal(t, plugin.ErrorCodeValidation, plgErr.ErrCode, "error code mismatch")
+			assert.Contains(t, plgErr.Message, "is not a valid AWS Signer ARN")
+		})
+	}
+}
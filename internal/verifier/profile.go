@@ -0,0 +1,169 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package verifier
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
+	"github.com/aws/aws-sdk-go-v2/service/signer"
+	"github.com/aws/aws-sdk-go-v2/service/signer/types"
+	"github.com/aws/aws-signer-notation-plugin/internal/client"
+	"github.com/aws/aws-signer-notation-plugin/internal/logger"
+	"github.com/aws/aws-signer-notation-plugin/internal/tracing"
+
+	"github.com/notaryproject/notation-plugin-framework-go/plugin"
+)
+
+// profileCacheTTL is the duration for which a signing profile is cached.
+const profileCacheTTL = 5 * time.Minute
+
+// cachedProfile is the part of GetSigningProfile API output used for verification.
+type cachedProfile struct {
+	status                  types.SigningProfileStatus
+	version                 string
+	revocationEffectiveFrom *time.Time
+	expiresAt               time.Time
+}
+
+// ProfileCache caches signing profiles by signing profile ARN, so that verifying many signatures of the same signing
+// profile calls GetSigningProfile API once. It is safe for concurrent use.
+type ProfileCache struct {
+	mu      sync.Mutex
+	entries map[string]cachedProfile
+}
+
+// NewProfileCache returns an empty ProfileCache.
+func NewProfileCache() *ProfileCache {
+	return &ProfileCache{entries: map[string]cachedProfile{}}
+}
+
+func (c *ProfileCache) get(profileArn string) (cachedProfile, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[profileArn]
+	return entry, ok
+}
+
+func (c *ProfileCache) set(profileArn string, entry cachedProfile) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[profileArn] = entry
+}
+
+// validateProfileStatus fails the trusted identity check if the signing profile of the signature is not active,
+// e.g. because it was canceled or revoked, or if the signing profile version of the signature is not the active
+// version of the signing profile.
+func (v *Verifier) validateProfileStatus(ctx context.Context, request *plugin.VerifySignatureRequest, results map[plugin.Capability]*result) error {
+	res := results[plugin.CapabilityTrustedIdentityVerifier]
+	if res == nil || !res.success {
+		return nil
+	}
+	profileVersionArn, err := getValueAsString(request.Signature.CriticalAttributes.ExtendedAttributes, attrSigningProfileVersion)
+	if err != nil {
+		return err
+	}
+	profileVersion, err := parseProfileVersionArn(profileVersionArn)
+	if err != nil {
+		return err
+	}
+
+	profile, err := v.getSigningProfile(ctx, profileVersion)
+	if err != nil {
+		return err
+	}
+	if profile.status != types.SigningProfileStatusActive {
+		res.success = false
+		res.Code = codeProfileNotActive
+		res.Message = fmt.Sprintf(reasonProfileNotActiveFmt, profileVersion.profileArn(), profile.status)
+		return nil
+	}
+	// GetSigningProfile API describes the active version, signatures of earlier versions are from retired versions
+	if profile.version != profileVersion.version {
+		res.success = false
+		res.Code = codeProfileVersionNotActive
+		res.Message = fmt.Sprintf(reasonProfileVersionNotActiveFmt, profileVersion.arn, profile.version)
+	}
+	return nil
+}
+
+// signingProfileVersion is the signing profile version in the signingProfileVersion extended attribute.
+type signingProfileVersion struct {
+	arn     string
+	owner   string
+	name    string
+	version string
+}
+
+// parseProfileVersionArn parses signing profile version ARN, e.g.
+// arn:aws:signer:us-west-2:111122223333:/signing-profiles/Profile/abc123.
+func parseProfileVersionArn(profileVersionArn string) (signingProfileVersion, error) {
+	a, err := arn.Parse(profileVersionArn)
+	if err != nil {
+		return signingProfileVersion{}, plugin.NewValidationErrorf(errMsgAttributeParse, attrSigningProfileVersion)
+	}
+	resource := strings.Split(a.Resource, "/")
+	if len(resource) != 4 {
+		return signingProfileVersion{}, plugin.NewValidationErrorf(errMsgAttributeParse, attrSigningProfileVersion)
+	}
+	return signingProfileVersion{arn: profileVersionArn, owner: a.AccountID, name: resource[2], version: resource[3]}, nil
+}
+
+// profileArn returns the ARN of the signing profile, i.e. the signing profile version ARN without the version.
+func (p signingProfileVersion) profileArn() string {
+	return p.arn[:strings.LastIndex(p.arn, "/")]
+}
+
+// getSigningProfile returns the signing profile of the given signing profile version, calling GetSigningProfile API
+// unless it is cached. GetSigningProfile API describes the active version of the signing profile, which may not be
+// the given version.
+func (v *Verifier) getSigningProfile(ctx context.Context, profileVersion signingProfileVersion) (cachedProfile, error) {
+	log := logger.GetLogger(ctx)
+	profileArn := profileVersion.profileArn()
+	now := v.now()
+	entry, ok := v.profiles.get(profileArn)
+	if ok && now.Before(entry.expiresAt) {
+		log.Debugf("using cached signing profile. status: %s, version: %s\n", entry.status, entry.version)
+		return entry, nil
+	}
+
+	log.Debug("calling AWS Signer's GetSigningProfile API")
+	profileCtx, profileSpan := tracing.Start(ctx, "Signer.GetSigningProfile", tracing.ProfileAttributes(profileArn)...)
+	output, err := v.awssigner.GetSigningProfile(profileCtx, &signer.GetSigningProfileInput{
+		ProfileName:  &profileVersion.name,
+		ProfileOwner: &profileVersion.owner,
+	})
+	tracing.End(profileSpan, err)
+	if err != nil {
+		log.With(logger.FieldRequestID, client.RequestID(err)).Debugf("failed AWS Signer's GetSigningProfile API call with error: %v\n", err)
+		return cachedProfile{}, plugin.NewError(client.ErrorCode(err), fmt.Sprintf("GetSigningProfile call failed with error: %v", err))
+	}
+	log.With(logger.FieldRequestID, client.ResultRequestID(output.ResultMetadata)).Debugf("succeeded AWS Signer's GetSigningProfile API call. status: %s, version: %s\n", output.Status, aws.ToString(output.ProfileVersion))
+
+	entry = cachedProfile{
+		status:    output.Status,
+		version:   aws.ToString(output.ProfileVersion),
+		expiresAt: now.Add(profileCacheTTL),
+	}
+	if output.RevocationRecord != nil {
+		entry.revocationEffectiveFrom = output.RevocationRecord.RevocationEffectiveFrom
+	}
+	v.profiles.set(profileArn, entry)
+	return entry, nil
+}
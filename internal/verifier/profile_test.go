@@ -0,0 +1,156 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package verifier
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/signer"
+	"github.com/aws/aws-sdk-go-v2/service/signer/types"
+	"github.com/aws/smithy-go"
+	"github.com/golang/mock/gomock"
+	"github.com/notaryproject/notation-plugin-framework-go/plugin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/aws/aws-signer-notation-plugin/internal/client"
+)
+
+func TestVerify_ProfileStatus(t *testing.T) {
+	tests := map[string]struct {
+		status         types.SigningProfileStatus
+		version        string
+		expectedResult plugin.VerificationResult
+	}{
+		"Active": {
+			status:         types.SigningProfileStatusActive,
+			version:        "OF8IVUsPJq",
+			expectedResult: plugin.VerificationResult{Success: true, Reason: testTISuccessReason},
+		},
+		"RetiredVersion": {
+			status:         types.SigningProfileStatusActive,
+			version:        "NewVersion",
+			expectedResult: plugin.VerificationResult{Success: false, Reason: fmt.Sprintf(reasonProfileVersionNotActiveFmt, testProfileVersionArn, "NewVersion")},
+		},
+		"Canceled": {
+			status:         types.SigningProfileStatusCanceled,
+			version:        "OF8IVUsPJq",
+			expectedResult: plugin.VerificationResult{Success: false, Reason: fmt.Sprintf(reasonProfileNotActiveFmt, testProfileArn, "Canceled")},
+		},
+		"Revoked": {
+			status:         types.SigningProfileStatusRevoked,
+			version:        "OF8IVUsPJq",
+			expectedResult: plugin.VerificationResult{Success: false, Reason: fmt.Sprintf(reasonProfileNotActiveFmt, testProfileArn, "Revoked")},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			mockSignerClient := client.NewMockInterface(mockCtrl)
+			mockSignerClient.EXPECT().GetSigningProfile(gomock.Any(), &signer.GetSigningProfileInput{
+				ProfileName:  aws.String("NotaryPluginIntegProfile"),
+				ProfileOwner: aws.String("000000000000"),
+			}).Return(&signer.GetSigningProfileOutput{Status: test.status, ProfileVersion: aws.String(test.version)}, nil)
+
+			resp, err := New(mockSignerClient).Verify(context.TODO(), mockProfileStatusRequest())
+			assert.NoError(t, err, "Verify() returned error")
+			assert.Equal(t, test.expectedResult, *resp.VerificationResults[plugin.CapabilityTrustedIdentityVerifier])
+		})
+	}
+}
+
+func TestVerify_ProfileStatusCache(t *testing.T) {
+	now := time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	mockSignerClient := client.NewMockInterface(mockCtrl)
+	mockSignerClient.EXPECT().GetSigningProfile(gomock.Any(), gomock.Any()).Return(&signer.GetSigningProfileOutput{Status: types.SigningProfileStatusActive, ProfileVersion: aws.String("OF8IVUsPJq")}, nil).Times(2)
+
+	v := New(mockSignerClient, WithClock(func() time.Time { return now }))
+	for i := 0; i < 3; i++ {
+		_, err := v.Verify(context.TODO(), mockProfileStatusRequest())
+		assert.NoError(t, err, "Verify() returned error")
+	}
+	now = now.Add(profileCacheTTL)
+	_, err := v.Verify(context.TODO(), mockProfileStatusRequest())
+	assert.NoError(t, err, "Verify() returned error")
+}
+
+func TestVerify_ProfileCache(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	mockSignerClient := client.NewMockInterface(mockCtrl)
+	mockSignerClient.EXPECT().GetSigningProfile(gomock.Any(), gomock.Any()).Return(&signer.GetSigningProfileOutput{Status: types.SigningProfileStatusActive, ProfileVersion: aws.String("OF8IVUsPJq")}, nil).Times(2)
+
+	// Verifiers share the signing profiles only through WithProfileCache
+	profiles := NewProfileCache()
+	for _, v := range []*Verifier{New(mockSignerClient, WithProfileCache(profiles)), New(mockSignerClient, WithProfileCache(profiles)), New(mockSignerClient)} {
+		_, err := v.Verify(context.TODO(), mockProfileStatusRequest())
+		assert.NoError(t, err, "Verify() returned error")
+	}
+}
+
+func TestVerify_ProfileStatusSkipped(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	mockSignerClient := client.NewMockInterface(mockCtrl)
+
+	t.Run("Disabled", func(t *testing.T) {
+		request := mockProfileStatusRequest()
+		request.PluginConfig[configKeyProfileStatusCheck] = "false"
+		resp, err := New(mockSignerClient).Verify(context.TODO(), request)
+		assert.NoError(t, err, "Verify() returned error")
+		assert.True(t, resp.VerificationResults[plugin.CapabilityTrustedIdentityVerifier].Success)
+	})
+
+	t.Run("UntrustedIdentity", func(t *testing.T) {
+		request := mockProfileStatusRequest()
+		request.TrustPolicy.TrustedIdentities = []string{"arn:aws:signer:us-west-2:000000000000:/signing-profiles/OtherProfile"}
+		resp, err := New(mockSignerClient).Verify(context.TODO(), request)
+		assert.NoError(t, err, "Verify() returned error")
+		assert.Equal(t, reasonTrustedIdentityFailure, resp.VerificationResults[plugin.CapabilityTrustedIdentityVerifier].Reason)
+	})
+}
+
+func TestVerify_ProfileStatusError(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	mockSignerClient := client.NewMockInterface(mockCtrl)
+	mockSignerClient.EXPECT().GetSigningProfile(gomock.Any(), gomock.Any()).Return(nil, &smithy.GenericAPIError{Code: "AccessDeniedException", Message: "not authorized"})
+
+	v := New(mockSignerClient)
+	_, err := v.Verify(context.TODO(), mockProfileStatusRequest())
+	assert.Equal(t, plugin.ErrorCodeAccessDenied, toPluginError(err, t).ErrCode)
+	assert.Contains(t, err.Error(), "GetSigningProfile call failed")
+	assert.Empty(t, v.profiles.entries, "failed lookup should not be cached")
+}
+
+func TestVerify_ProfileStatusInvalidConfig(t *testing.T) {
+	request := mockProfileStatusRequest()
+	request.PluginConfig[configKeyProfileStatusCheck] = "yes"
+	_, err := New(nil).Verify(context.TODO(), request)
+	assert.Equal(t, fmt.Sprintf(errMsgInvalidConfigValueFmt, "yes", configKeyProfileStatusCheck, "true, false"), toPluginError(err, t).Message)
+}
+
+// mockProfileStatusRequest returns request with signing profile status check enabled and revocation check disabled.
+func mockProfileStatusRequest() *plugin.VerifySignatureRequest {
+	request := mockVerifySigRequest()
+	request.TrustPolicy.SignatureVerification = []plugin.Capability{plugin.CapabilityTrustedIdentityVerifier}
+	request.PluginConfig = map[string]string{configKeyProfileStatusCheck: "true"}
+	return request
+}
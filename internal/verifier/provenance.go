@@ -0,0 +1,121 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package verifier
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
+	"github.com/aws/aws-sdk-go-v2/service/signer"
+	"github.com/aws/aws-signer-notation-plugin/internal/client"
+	"github.com/aws/aws-signer-notation-plugin/internal/logger"
+	"github.com/aws/aws-signer-notation-plugin/internal/tracing"
+
+	"github.com/notaryproject/notation-plugin-framework-go/plugin"
+)
+
+const signingJobResourcePrefix = "/signing-jobs/"
+
+// isProvenanceCheckEnabled returns true if the provenance check is enabled through plugin config. Setting the
+// allowed requesters enables the check, so that the allow-list is never silently ignored.
+func isProvenanceCheckEnabled(pluginConfig map[string]string) bool {
+	if _, ok := pluginConfig[configKeyAllowedRequesters]; ok {
+		return true
+	}
+	return pluginConfig[configKeyProvenanceCheck] == "true"
+}
+
+// validateProvenance fails the trusted identity check if the signing job of the signature doesn't belong to the
+// signing profile version of the signature and its owner account, or if it wasn't requested by one of the allowed
+// requesters. Otherwise, the principal which requested the signing job is added to the reason of the trusted identity result.
+func (v *Verifier) validateProvenance(ctx context.Context, request *plugin.VerifySignatureRequest, results map[plugin.Capability]*result) error {
+	res := results[plugin.CapabilityTrustedIdentityVerifier]
+	if res == nil || !res.success {
+		return nil
+	}
+	attrs := request.Signature.CriticalAttributes.ExtendedAttributes
+	profileVersionArn, err := getValueAsString(attrs, attrSigningProfileVersion)
+	if err != nil {
+		return err
+	}
+	profileVersion, err := parseProfileVersionArn(profileVersionArn)
+	if err != nil {
+		return err
+	}
+	jobArn, err := getValueAsString(attrs, attrSigningJob)
+	if err != nil {
+		return err
+	}
+	a, err := arn.Parse(jobArn)
+	if err != nil || !strings.HasPrefix(a.Resource, signingJobResourcePrefix) {
+		return plugin.NewValidationErrorf(errMsgAttributeParse, attrSigningJob)
+	}
+
+	log := logger.GetLogger(ctx)
+	log.Debug("calling AWS Signer's DescribeSigningJob API")
+	jobCtx, jobSpan := tracing.Start(ctx, "Signer.DescribeSigningJob", tracing.ProfileAttributes(profileVersionArn)...)
+	output, err := v.awssigner.DescribeSigningJob(jobCtx, &signer.DescribeSigningJobInput{
+		JobId: aws.String(strings.TrimPrefix(a.Resource, signingJobResourcePrefix)),
+	})
+	tracing.End(jobSpan, err)
+	if err != nil {
+		log.With(logger.FieldRequestID, client.RequestID(err)).Debugf("failed AWS Signer's DescribeSigningJob API call with error: %v\n", err)
+		return plugin.NewError(client.ErrorCode(err), fmt.Sprintf("DescribeSigningJob call failed with error: %v", err))
+	}
+	requestedBy := aws.ToString(output.RequestedBy)
+	log.With(logger.FieldRequestID, client.ResultRequestID(output.ResultMetadata)).Debugf("succeeded AWS Signer's DescribeSigningJob API call. job owner: %s, signing profile: %s, version: %s, requested by: %s\n",
+		aws.ToString(output.JobOwner), aws.ToString(output.ProfileName), aws.ToString(output.ProfileVersion), requestedBy)
+
+	res.RequestedBy = requestedBy
+	// signing profile names and versions are matched case-insensitively, like trusted identities
+	if aws.ToString(output.JobOwner) != profileVersion.owner ||
+		!strings.EqualFold(aws.ToString(output.ProfileName), profileVersion.name) ||
+		!strings.EqualFold(aws.ToString(output.ProfileVersion), profileVersion.version) {
+		res.success = false
+		res.Code = codeJobProfileMismatch
+		res.Message = fmt.Sprintf(reasonJobProfileMismatchFmt, jobArn, profileVersionArn)
+		return nil
+	}
+	if allowed, ok := request.PluginConfig[configKeyAllowedRequesters]; ok && !isAllowedRequester(requestedBy, allowed) {
+		res.success = false
+		res.Code = codeRequesterNotAllowed
+		res.Message = fmt.Sprintf(reasonJobRequesterNotAllowedFmt, jobArn, requestedBy)
+		return nil
+	}
+	res.Message = res.Message + " " + fmt.Sprintf(reasonJobRequesterFmt, jobArn, requestedBy)
+	return nil
+}
+
+// isAllowedRequester returns true if requestedBy matches any of the comma separated allowed requesters. An allowed
+// requester ending with "*" matches any principal starting with the rest of it, e.g.
+// arn:aws:sts::111122223333:assumed-role/SigningRole/* matches all sessions of the SigningRole role.
+func isAllowedRequester(requestedBy, allowedRequesters string) bool {
+	if requestedBy == "" {
+		return false
+	}
+	for _, allowed := range strings.Split(allowedRequesters, ",") {
+		allowed = strings.TrimSpace(allowed)
+		if prefix, ok := strings.CutSuffix(allowed, "*"); ok {
+			if strings.HasPrefix(requestedBy, prefix) {
+				return true
+			}
+		} else if allowed != "" && requestedBy == allowed {
+			return true
+		}
+	}
+	return false
+}
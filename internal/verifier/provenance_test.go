@@ -0,0 +1,187 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package verifier
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/signer"
+	"github.com/aws/smithy-go"
+	"github.com/golang/mock/gomock"
+	"github.com/notaryproject/notation-plugin-framework-go/plugin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/aws/aws-signer-notation-plugin/internal/client"
+)
+
+const testRequestedBy = "arn:aws:sts::000000000000:assumed-role/SigningRole/build-1234"
+
+func TestVerify_Provenance(t *testing.T) {
+	tests := map[string]struct {
+		pluginConfig   map[string]string
+		jobOwner       string
+		profileName    string
+		profileVersion string
+		expectedResult plugin.VerificationResult
+	}{
+		"Enabled": {
+			pluginConfig:   map[string]string{configKeyProvenanceCheck: "true"},
+			expectedResult: plugin.VerificationResult{Success: true, Reason: testTISuccessReason + " " + fmt.Sprintf(reasonJobRequesterFmt, testJobArn, testRequestedBy)},
+		},
+		"AllowedRequester": {
+			pluginConfig:   map[string]string{configKeyAllowedRequesters: "arn:aws:iam::000000000000:user/Alice, " + testRequestedBy},
+			expectedResult: plugin.VerificationResult{Success: true, Reason: testTISuccessReason + " " + fmt.Sprintf(reasonJobRequesterFmt, testJobArn, testRequestedBy)},
+		},
+		"AllowedRequesterPrefix": {
+			pluginConfig:   map[string]string{configKeyAllowedRequesters: "arn:aws:sts::000000000000:assumed-role/SigningRole/*"},
+			expectedResult: plugin.VerificationResult{Success: true, Reason: testTISuccessReason + " " + fmt.Sprintf(reasonJobRequesterFmt, testJobArn, testRequestedBy)},
+		},
+		"NotAllowedRequester": {
+			pluginConfig:   map[string]string{configKeyProvenanceCheck: "true", configKeyAllowedRequesters: "arn:aws:iam::000000000000:user/Alice,arn:aws:sts::000000000000:assumed-role/OtherRole/*"},
+			expectedResult: plugin.VerificationResult{Success: false, Reason: fmt.Sprintf(reasonJobRequesterNotAllowedFmt, testJobArn, testRequestedBy)},
+		},
+		"ProfileMismatch": {
+			pluginConfig:   map[string]string{configKeyProvenanceCheck: "true"},
+			profileName:    "OtherProfile",
+			expectedResult: plugin.VerificationResult{Success: false, Reason: fmt.Sprintf(reasonJobProfileMismatchFmt, testJobArn, testProfileVersionArn)},
+		},
+		"ProfileCaseInsensitive": {
+			pluginConfig:   map[string]string{configKeyProvenanceCheck: "true"},
+			profileName:    "notarypluginintegprofile",
+			profileVersion: "of8ivuspjq",
+			expectedResult: plugin.VerificationResult{Success: true, Reason: testTISuccessReason + " " + fmt.Sprintf(reasonJobRequesterFmt, testJobArn, testRequestedBy)},
+		},
+		"JobOwnerMismatch": {
+			pluginConfig:   map[string]string{configKeyProvenanceCheck: "true"},
+			jobOwner:       "111122223333",
+			expectedResult: plugin.VerificationResult{Success: false, Reason: fmt.Sprintf(reasonJobProfileMismatchFmt, testJobArn, testProfileVersionArn)},
+		},
+		"ProfileVersionMismatch": {
+			pluginConfig:   map[string]string{configKeyProvenanceCheck: "true"},
+			profileVersion: "OtherVersion",
+			expectedResult: plugin.VerificationResult{Success: false, Reason: fmt.Sprintf(reasonJobProfileMismatchFmt, testJobArn, testProfileVersionArn)},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			output := &signer.DescribeSigningJobOutput{
+				JobOwner:       aws.String("000000000000"),
+				ProfileName:    aws.String("NotaryPluginIntegProfile"),
+				ProfileVersion: aws.String("OF8IVUsPJq"),
+				RequestedBy:    aws.String(testRequestedBy),
+			}
+			if test.jobOwner != "" {
+				output.JobOwner = aws.String(test.jobOwner)
+			}
+			if test.profileName != "" {
+				output.ProfileName = aws.String(test.profileName)
+			}
+			if test.profileVersion != "" {
+				output.ProfileVersion = aws.String(test.profileVersion)
+			}
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			mockSignerClient := client.NewMockInterface(mockCtrl)
+			mockSignerClient.EXPECT().DescribeSigningJob(gomock.Any(), &signer.DescribeSigningJobInput{
+				JobId: aws.String("97af3947-e7b2-4533-8d9d-6741156f0b79"),
+			}).Return(output, nil)
+
+			request := mockVerifySigRequest()
+			request.TrustPolicy.SignatureVerification = []plugin.Capability{plugin.CapabilityTrustedIdentityVerifier}
+			request.PluginConfig = test.pluginConfig
+			resp, err := New(mockSignerClient).Verify(context.TODO(), request)
+			assert.NoError(t, err, "Verify() returned error")
+			assert.Equal(t, test.expectedResult, *resp.VerificationResults[plugin.CapabilityTrustedIdentityVerifier])
+		})
+	}
+}
+
+func TestVerify_ProvenanceSkipped(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	mockSignerClient := client.NewMockInterface(mockCtrl)
+
+	t.Run("Disabled", func(t *testing.T) {
+		request := mockVerifySigRequest()
+		request.TrustPolicy.SignatureVerification = []plugin.Capability{plugin.CapabilityTrustedIdentityVerifier}
+		request.PluginConfig = map[string]string{configKeyProvenanceCheck: "false"}
+		resp, err := New(mockSignerClient).Verify(context.TODO(), request)
+		assert.NoError(t, err, "Verify() returned error")
+		assert.Equal(t, testTISuccessReason, resp.VerificationResults[plugin.CapabilityTrustedIdentityVerifier].Reason)
+	})
+
+	t.Run("UntrustedIdentity", func(t *testing.T) {
+		request := mockVerifySigRequest()
+		request.TrustPolicy.SignatureVerification = []plugin.Capability{plugin.CapabilityTrustedIdentityVerifier}
+		request.TrustPolicy.TrustedIdentities = []string{"arn:aws:signer:us-west-2:000000000000:/signing-profiles/OtherProfile"}
+		request.PluginConfig = map[string]string{configKeyProvenanceCheck: "true"}
+		resp, err := New(mockSignerClient).Verify(context.TODO(), request)
+		assert.NoError(t, err, "Verify() returned error")
+		assert.Equal(t, reasonTrustedIdentityFailure, resp.VerificationResults[plugin.CapabilityTrustedIdentityVerifier].Reason)
+	})
+}
+
+func TestVerify_ProvenanceError(t *testing.T) {
+	t.Run("APIError", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+		mockSignerClient := client.NewMockInterface(mockCtrl)
+		mockSignerClient.EXPECT().DescribeSigningJob(gomock.Any(), gomock.Any()).Return(nil, &smithy.GenericAPIError{Code: "ThrottlingException", Message: "Rate exceeded"})
+
+		request := mockVerifySigRequest()
+		request.PluginConfig = map[string]string{configKeyProvenanceCheck: "true"}
+		_, err := New(mockSignerClient).Verify(context.TODO(), request)
+		assert.Equal(t, plugin.ErrorCodeThrottled, toPluginError(err, t).ErrCode)
+		assert.Contains(t, err.Error(), "DescribeSigningJob call failed")
+	})
+
+	t.Run("InvalidJobArn", func(t *testing.T) {
+		request := mockVerifySigRequest()
+		request.Signature.CriticalAttributes.ExtendedAttributes[attrSigningJob] = testProfileVersionArn
+		request.PluginConfig = map[string]string{configKeyProvenanceCheck: "true"}
+		_, err := New(nil).Verify(context.TODO(), request)
+		assert.Equal(t, fmt.Sprintf(errMsgAttributeParse, attrSigningJob), toPluginError(err, t).Message)
+	})
+
+	t.Run("InvalidConfig", func(t *testing.T) {
+		request := mockVerifySigRequest()
+		request.PluginConfig = map[string]string{configKeyProvenanceCheck: "enabled"}
+		_, err := New(nil).Verify(context.TODO(), request)
+		assert.Equal(t, fmt.Sprintf(errMsgInvalidConfigValueFmt, "enabled", configKeyProvenanceCheck, "true, false"), toPluginError(err, t).Message)
+	})
+}
+
+func TestIsAllowedRequester(t *testing.T) {
+	tests := map[string]struct {
+		requestedBy       string
+		allowedRequesters string
+		expected          bool
+	}{
+		"exact":           {requestedBy: testRequestedBy, allowedRequesters: testRequestedBy, expected: true},
+		"list":            {requestedBy: testRequestedBy, allowedRequesters: "arn:aws:iam::000000000000:user/Alice , " + testRequestedBy + " ", expected: true},
+		"prefix":          {requestedBy: testRequestedBy, allowedRequesters: "arn:aws:sts::000000000000:assumed-role/SigningRole/*", expected: true},
+		"noMatch":         {requestedBy: testRequestedBy, allowedRequesters: "arn:aws:sts::000000000000:assumed-role/SigningRole", expected: false},
+		"emptyList":       {requestedBy: testRequestedBy, allowedRequesters: "", expected: false},
+		"emptyRequester":  {requestedBy: "", allowedRequesters: "*", expected: false},
+		"caseSensitivity": {requestedBy: testRequestedBy, allowedRequesters: "arn:aws:sts::000000000000:assumed-role/signingrole/*", expected: false},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.expected, isAllowedRequester(test.requestedBy, test.allowedRequesters))
+		})
+	}
+}
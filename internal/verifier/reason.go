@@ -0,0 +1,140 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package verifier
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/notaryproject/notation-plugin-framework-go/plugin"
+)
+
+// Reason codes of verification results. The codes are reported when aws-signer-reason-format plugin config is code
+// or json, and are documented in README, so they must not be changed.
+const (
+	codeIdentityMatched               = "IDENTITY_MATCHED"
+	codeIdentityNotMatched            = "IDENTITY_NOT_MATCHED"
+	codeProfileNotActive              = "PROFILE_NOT_ACTIVE"
+	codeProfileVersionNotActive       = "PROFILE_VERSION_NOT_ACTIVE"
+	codeJobProfileMismatch            = "JOB_PROFILE_MISMATCH"
+	codeRequesterNotAllowed           = "REQUESTER_NOT_ALLOWED"
+	codeSigningTimeInFuture           = "SIGNING_TIME_IN_FUTURE"
+	codeSigningTimeOutsideCertificate = "SIGNING_TIME_OUTSIDE_CERTIFICATE_VALIDITY"
+	codeSigningTimeAfterRevocation    = "SIGNING_TIME_AFTER_REVOCATION"
+	codeNotRevoked                    = "NOT_REVOKED"
+	codeRevoked                       = "REVOKED"
+	codeRevocationCheckFailed         = "REVOCATION_CHECK_FAILED"
+	codeRevocationStatusUnknown       = "REVOCATION_STATUS_UNKNOWN"
+	codeRevocationCheckSkipped        = "REVOCATION_CHECK_SKIPPED"
+)
+
+const (
+	configKeyReasonFormat = "aws-signer-reason-format"
+	reasonFormatText      = "text"
+	reasonFormatCode      = "code"
+	reasonFormatJSON      = "json"
+)
+
+var reasonFormats = []string{
+	reasonFormatText,
+	reasonFormatCode,
+	reasonFormatJSON}
+
+// result is the result of a verification capability along with its structured reason.
+type result struct {
+	success bool
+	reason
+}
+
+// reason is the structured reason of a verification result. Message is the free-text reason, which is the reason
+// reported by default unless text is set. text keeps the free-text reason of the text format backwards compatible
+// where Message is worded differently.
+type reason struct {
+	Code            string           `json:"code"`
+	Message         string           `json:"message"`
+	MatchedIdentity string           `json:"matchedIdentity,omitempty"`
+	RequestedBy     string           `json:"requestedBy,omitempty"`
+	RevokedEntities *revokedEntities `json:"revokedEntities,omitempty"`
+	text            string
+}
+
+// revokedEntities are the revoked entities returned by GetRevocationStatus API, by type. Certificates are the
+// certificates of the certificate chain whose hashes are revoked.
+type revokedEntities struct {
+	ProfileVersions   []string             `json:"profileVersions,omitempty"`
+	Jobs              []string             `json:"jobs,omitempty"`
+	CertificateHashes []string             `json:"certificateHashes,omitempty"`
+	Certificates      []revokedCertificate `json:"certificates,omitempty"`
+}
+
+// revokedCertificate is a certificate of the certificate chain whose hash is revoked.
+type revokedCertificate struct {
+	Position     int    `json:"position"`
+	Subject      string `json:"subject"`
+	SerialNumber string `json:"serialNumber"`
+	Fingerprint  string `json:"fingerprint"`
+	Hash         string `json:"hash"`
+}
+
+// format returns the reason in the given reason format. The text format is the free-text reason, the code format
+// prefixes it with the reason code in square brackets, e.g. "[NOT_REVOKED] Signature is not revoked.", and the json
+// format is the reason as JSON object.
+func (r reason) format(format string) string {
+	switch format {
+	case reasonFormatCode:
+		return "[" + r.Code + "] " + r.Message
+	case reasonFormatJSON:
+		b, err := json.Marshal(r)
+		if err != nil {
+			return r.Message
+		}
+		return string(b)
+	default:
+		if r.text != "" {
+			return r.text
+		}
+		return r.Message
+	}
+}
+
+// toVerificationResults converts results to the verification results of the plugin contract, using the reason
+// format configured through plugin config.
+func toVerificationResults(results map[plugin.Capability]*result, pluginConfig map[string]string) map[plugin.Capability]*plugin.VerificationResult {
+	format := pluginConfig[configKeyReasonFormat]
+	verificationResults := make(map[plugin.Capability]*plugin.VerificationResult, len(results))
+	for capability, res := range results {
+		verificationResults[capability] = &plugin.VerificationResult{
+			Success: res.success,
+			Reason:  res.format(format),
+		}
+	}
+	return verificationResults
+}
+
+// classifyRevokedEntities groups the revoked entities returned by GetRevocationStatus API by type. Entities which
+// aren't ARNs are certificate hashes.
+func classifyRevokedEntities(entities []string) *revokedEntities {
+	res := &revokedEntities{}
+	for _, entity := range entities {
+		switch {
+		case !strings.HasPrefix(entity, "arn"):
+			res.CertificateHashes = append(res.CertificateHashes, entity)
+		case strings.Contains(entity, signingJobResourcePrefix):
+			res.Jobs = append(res.Jobs, entity)
+		default:
+			res.ProfileVersions = append(res.ProfileVersions, entity)
+		}
+	}
+	return res
+}
@@ -0,0 +1,171 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package verifier
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/signer"
+	"github.com/notaryproject/notation-plugin-framework-go/plugin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReason_Format(t *testing.T) {
+	r := reason{Code: codeNotRevoked, Message: reasonNotRevoked}
+	tests := map[string]struct {
+		format   string
+		expected string
+	}{
+		"default": {format: "", expected: reasonNotRevoked},
+		"text":    {format: reasonFormatText, expected: reasonNotRevoked},
+		"code":    {format: reasonFormatCode, expected: "[NOT_REVOKED] Signature is not revoked."},
+		"json":    {format: reasonFormatJSON, expected: `{"code":"NOT_REVOKED","message":"Signature is not revoked."}`},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.expected, r.format(test.format))
+		})
+	}
+}
+
+func TestReason_FormatRevoked(t *testing.T) {
+	r := getRevocationResultReason([]string{testJobArn, testCertificate1Hash + testCertificate2Hash}, nil, nil)
+	// the text format is kept the same as in earlier plugin versions
+	assert.Equal(t, "Resource(s) "+testJobArn+" have been revoked.Certificate(s) have been revoked.", r.format(reasonFormatText))
+	assert.Equal(t, "[REVOKED] Resource(s) "+testJobArn+" have been revoked. Certificate(s) have been revoked.", r.format(reasonFormatCode))
+}
+
+func TestVerify_ReasonFormatJSON(t *testing.T) {
+	revoked := []string{testJobArn, testProfileVersionArn, testCertificate1Hash + testCertificate2Hash}
+	mockSignerClient, mockCtrl := getMockClient(nil, &signer.GetRevocationStatusOutput{RevokedEntities: revoked}, nil, t)
+	defer mockCtrl.Finish()
+
+	request := mockVerifySigRequest()
+	request.PluginConfig = map[string]string{configKeyReasonFormat: reasonFormatJSON}
+	resp, err := New(mockSignerClient).Verify(context.TODO(), request)
+	if !assert.NoError(t, err, "Verify() returned error") {
+		return
+	}
+
+	var identityReason reason
+	assert.NoError(t, json.Unmarshal([]byte(resp.VerificationResults[plugin.CapabilityTrustedIdentityVerifier].Reason), &identityReason))
+	assert.Equal(t, reason{
+		Code:            codeIdentityMatched,
+		Message:         testTISuccessReason,
+		MatchedIdentity: testProfileArn,
+	}, identityReason)
+
+	var revocationReason reason
+	assert.NoError(t, json.Unmarshal([]byte(resp.VerificationResults[plugin.CapabilityRevocationCheckVerifier].Reason), &revocationReason))
+	assert.Equal(t, reason{
+		Code: codeRevoked,
+		Message: fmt.Sprintf(reasonRevokedResourceFmt, testJobArn+", "+testProfileVersionArn) + " " + reasonRevokedCertificate +
+			" " + fmt.Sprintf(reasonRevokedCertificateFmt, 0, "CN=foo.bar", testCertificate1Serial, testCertificate1Fingerprint),
+		RevokedEntities: &revokedEntities{
+			ProfileVersions:   []string{testProfileVersionArn},
+			Jobs:              []string{testJobArn},
+			CertificateHashes: []string{testCertificate1Hash + testCertificate2Hash},
+			Certificates: []revokedCertificate{{
+				Position:     0,
+				Subject:      "CN=foo.bar",
+				SerialNumber: testCertificate1Serial,
+				Fingerprint:  testCertificate1Fingerprint,
+				Hash:         testCertificate1Hash + testCertificate2Hash,
+			}},
+		},
+	}, revocationReason)
+}
+
+func TestVerify_ReasonFormatCode(t *testing.T) {
+	tests := map[string]struct {
+		revocationOutput   *signer.GetRevocationStatusOutput
+		revocationErr      error
+		revocationMode     string
+		trustedIdentity    string
+		expectedIdentity   string
+		expectedRevocation string
+	}{
+		"notRevoked": {
+			revocationOutput:   &signer.GetRevocationStatusOutput{},
+			expectedIdentity:   "[IDENTITY_MATCHED] " + testTISuccessReason,
+			expectedRevocation: "[NOT_REVOKED] " + reasonNotRevoked,
+		},
+		"untrustedIdentity": {
+			revocationOutput:   &signer.GetRevocationStatusOutput{},
+			trustedIdentity:    "arn:aws:signer:us-west-2:000000000000:/signing-profiles/OtherProfile",
+			expectedIdentity:   "[IDENTITY_NOT_MATCHED] " + reasonTrustedIdentityFailure,
+			expectedRevocation: "[NOT_REVOKED] " + reasonNotRevoked,
+		},
+		"revocationCheckFailed": {
+			revocationErr:      fmt.Errorf("unreachable"),
+			expectedIdentity:   "[IDENTITY_MATCHED] " + testTISuccessReason,
+			expectedRevocation: "[REVOCATION_CHECK_FAILED] " + fmt.Sprintf(reasonRevocationCheckFailedFmt, "GetRevocationStatus call failed with error: unreachable", revocationFailureModeEnforce),
+		},
+		"revokedJobAndCertificate": {
+			revocationOutput: &signer.GetRevocationStatusOutput{RevokedEntities: []string{testJobArn, testCertificate1Hash + testCertificate2Hash}},
+			expectedIdentity: "[IDENTITY_MATCHED] " + testTISuccessReason,
+			expectedRevocation: "[REVOKED] " + fmt.Sprintf(reasonRevokedResourceFmt, testJobArn) + " " + reasonRevokedCertificate +
+				" " + fmt.Sprintf(reasonRevokedCertificateFmt, 0, "CN=foo.bar", testCertificate1Serial, testCertificate1Fingerprint),
+		},
+		"revokedChainCertificates": {
+			revocationOutput: &signer.GetRevocationStatusOutput{RevokedEntities: []string{testCertificate2Hash + testCertificate2Hash, testCertificate1Hash + testCertificate2Hash}},
+			expectedIdentity: "[IDENTITY_MATCHED] " + testTISuccessReason,
+			expectedRevocation: "[REVOKED] " + reasonRevokedCertificate + " " + fmt.Sprintf(reasonRevokedCertificateFmt, 0, "CN=foo.bar", testCertificate1Serial, testCertificate1Fingerprint) +
+				" " + fmt.Sprintf(reasonRevokedCertificateFmt, 1, "O=chienb", testCertificate2Serial, testCertificate2Fingerprint),
+		},
+		"revocationStatusUnknown": {
+			revocationErr:      fmt.Errorf("unreachable"),
+			revocationMode:     revocationFailureModeWarn,
+			expectedIdentity:   "[IDENTITY_MATCHED] " + testTISuccessReason,
+			expectedRevocation: "[REVOCATION_STATUS_UNKNOWN] " + fmt.Sprintf(reasonRevocationCheckWarnFmt, "GetRevocationStatus call failed with error: unreachable", revocationFailureModeWarn),
+		},
+		"revocationCheckSkipped": {
+			revocationErr:      fmt.Errorf("unreachable"),
+			revocationMode:     revocationFailureModeSkip,
+			expectedIdentity:   "[IDENTITY_MATCHED] " + testTISuccessReason,
+			expectedRevocation: "[REVOCATION_CHECK_SKIPPED] " + fmt.Sprintf(reasonRevocationCheckSkippedFmt, revocationFailureModeSkip),
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockSignerClient, mockCtrl := getMockClient(nil, test.revocationOutput, test.revocationErr, t)
+			defer mockCtrl.Finish()
+
+			request := mockVerifySigRequest()
+			request.PluginConfig = map[string]string{configKeyReasonFormat: reasonFormatCode}
+			if test.revocationMode != "" {
+				request.PluginConfig[configKeyRevocationFailureMode] = test.revocationMode
+			}
+			if test.trustedIdentity != "" {
+				request.TrustPolicy.TrustedIdentities = []string{test.trustedIdentity}
+			}
+			resp, err := New(mockSignerClient).Verify(context.TODO(), request)
+			if !assert.NoError(t, err, "Verify() returned error") {
+				return
+			}
+			assert.Equal(t, test.expectedIdentity, resp.VerificationResults[plugin.CapabilityTrustedIdentityVerifier].Reason)
+			assert.Equal(t, test.expectedRevocation, resp.VerificationResults[plugin.CapabilityRevocationCheckVerifier].Reason)
+		})
+	}
+}
+
+func TestVerify_ReasonFormatInvalidConfig(t *testing.T) {
+	request := mockVerifySigRequest()
+	request.PluginConfig = map[string]string{configKeyReasonFormat: "xml"}
+	_, err := New(nil).Verify(context.TODO(), request)
+	assert.Equal(t, fmt.Sprintf(errMsgInvalidConfigValueFmt, "xml", configKeyReasonFormat, "text, code, json"), toPluginError(err, t).Message)
+}
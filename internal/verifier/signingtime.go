@@ -0,0 +1,100 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package verifier
+
+import (
+	"context"
+	"crypto/x509"
+	"fmt"
+	"time"
+
+	"github.com/notaryproject/notation-plugin-framework-go/plugin"
+)
+
+// defaultClockSkew is the default tolerance for authentic signing time later than the current time.
+const defaultClockSkew = 5 * time.Minute
+
+// validateSigningTime fails the trusted identity check if the authentic signing time of the signature is later than
+// the current time plus the clock skew tolerance, or is outside the window in which the signing profile version could
+// sign, i.e. outside the validity period of the signing certificate or not earlier than the revocation of the signing
+// profile version.
+func (v *Verifier) validateSigningTime(ctx context.Context, request *plugin.VerifySignatureRequest, results map[plugin.Capability]*result) error {
+	res := results[plugin.CapabilityTrustedIdentityVerifier]
+	if res == nil || !res.success {
+		return nil
+	}
+	signingTime := request.Signature.CriticalAttributes.AuthenticSigningTime.UTC()
+	clockSkew := defaultClockSkew
+	if _, ok := request.PluginConfig[configKeyClockSkew]; ok {
+		clockSkew, _ = getDurationConfig(request.PluginConfig, configKeyClockSkew)
+	}
+	now := v.now().UTC()
+	if signingTime.After(now.Add(clockSkew)) {
+		res.success = false
+		res.Code = codeSigningTimeInFuture
+		res.Message = fmt.Sprintf(reasonSigningTimeInFutureFmt, formatTime(signingTime), formatTime(now), clockSkew)
+		return nil
+	}
+
+	if len(request.Signature.CertificateChain) == 0 {
+		return plugin.NewValidationError(errMsgCertificateParse)
+	}
+	cert, err := x509.ParseCertificate(request.Signature.CertificateChain[0])
+	if err != nil {
+		return plugin.NewValidationError(errMsgCertificateParse)
+	}
+	if signingTime.Before(cert.NotBefore) || signingTime.After(cert.NotAfter) {
+		res.success = false
+		res.Code = codeSigningTimeOutsideCertificate
+		res.Message = fmt.Sprintf(reasonSigningTimeOutsideCertificateFmt, formatTime(signingTime), formatTime(cert.NotBefore), formatTime(cert.NotAfter))
+		return nil
+	}
+
+	profileVersionArn, err := getValueAsString(request.Signature.CriticalAttributes.ExtendedAttributes, attrSigningProfileVersion)
+	if err != nil {
+		return err
+	}
+	profileVersion, err := parseProfileVersionArn(profileVersionArn)
+	if err != nil {
+		return err
+	}
+	profile, err := v.getSigningProfile(ctx, profileVersion)
+	if err != nil {
+		return err
+	}
+	// the revocation record describes the active version of the signing profile, earlier versions are only bounded by
+	// the validity period of the signing certificate
+	if profile.version == profileVersion.version && profile.revocationEffectiveFrom != nil && !signingTime.Before(*profile.revocationEffectiveFrom) {
+		res.success = false
+		res.Code = codeSigningTimeAfterRevocation
+		res.Message = fmt.Sprintf(reasonSigningTimeAfterRevocationFmt, formatTime(signingTime), formatTime(*profile.revocationEffectiveFrom), profileVersionArn)
+	}
+	return nil
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
+
+// isSigningTimeFailure returns true if res is the trusted identity result failed by validateSigningTime.
+func isSigningTimeFailure(res *result) bool {
+	if res == nil || res.success {
+		return false
+	}
+	switch res.Code {
+	case codeSigningTimeInFuture, codeSigningTimeOutsideCertificate, codeSigningTimeAfterRevocation:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,199 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package verifier
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/signer"
+	"github.com/aws/aws-sdk-go-v2/service/signer/types"
+	"github.com/golang/mock/gomock"
+	"github.com/notaryproject/notation-plugin-framework-go/plugin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/aws/aws-signer-notation-plugin/internal/client"
+)
+
+func TestVerify_SigningTime(t *testing.T) {
+	// the signing certificate is valid from 2022-07-15T17:24:41Z to 2023-08-15T18:24:40Z
+	now := time.Date(2022, 8, 1, 12, 0, 0, 0, time.UTC)
+	revokedAt := time.Date(2022, 7, 20, 0, 0, 0, 0, time.UTC)
+	tests := map[string]struct {
+		signingTime     time.Time
+		clockSkew       string
+		profile         *signer.GetSigningProfileOutput
+		expectedReason  string
+		expectedAPICall bool
+	}{
+		"valid": {
+			signingTime:     now.Add(-time.Hour),
+			profile:         &signer.GetSigningProfileOutput{ProfileVersion: aws.String("OF8IVUsPJq")},
+			expectedAPICall: true,
+		},
+		"withinClockSkew": {
+			signingTime:     now.Add(4 * time.Minute),
+			profile:         &signer.GetSigningProfileOutput{ProfileVersion: aws.String("OF8IVUsPJq")},
+			expectedAPICall: true,
+		},
+		"futureDated": {
+			signingTime:    now.Add(6 * time.Minute),
+			expectedReason: "[" + codeSigningTimeInFuture + "] " + fmt.Sprintf(reasonSigningTimeInFutureFmt, "2022-08-01T12:06:00Z", "2022-08-01T12:00:00Z", "5m0s"),
+		},
+		"futureDatedCustomClockSkew": {
+			signingTime:    now.Add(2 * time.Minute),
+			clockSkew:      "1m",
+			expectedReason: "[" + codeSigningTimeInFuture + "] " + fmt.Sprintf(reasonSigningTimeInFutureFmt, "2022-08-01T12:02:00Z", "2022-08-01T12:00:00Z", "1m0s"),
+		},
+		"beforeCertificate": {
+			signingTime:    time.Date(2022, 7, 6, 19, 10, 28, 0, time.UTC),
+			expectedReason: "[" + codeSigningTimeOutsideCertificate + "] " + fmt.Sprintf(reasonSigningTimeOutsideCertificateFmt, "2022-07-06T19:10:28Z", "2022-07-15T17:24:41Z", "2023-08-15T18:24:40Z"),
+		},
+		"afterRevocation": {
+			signingTime: now.Add(-time.Hour),
+			profile: &signer.GetSigningProfileOutput{
+				ProfileVersion:   aws.String("OF8IVUsPJq"),
+				Status:           types.SigningProfileStatusRevoked,
+				RevocationRecord: &types.SigningProfileRevocationRecord{RevocationEffectiveFrom: &revokedAt},
+			},
+			expectedReason:  "[" + codeSigningTimeAfterRevocation + "] " + fmt.Sprintf(reasonSigningTimeAfterRevocationFmt, "2022-08-01T11:00:00Z", "2022-07-20T00:00:00Z", testProfileVersionArn),
+			expectedAPICall: true,
+		},
+		"beforeRevocation": {
+			signingTime: time.Date(2022, 7, 19, 0, 0, 0, 0, time.UTC),
+			profile: &signer.GetSigningProfileOutput{
+				ProfileVersion:   aws.String("OF8IVUsPJq"),
+				Status:           types.SigningProfileStatusRevoked,
+				RevocationRecord: &types.SigningProfileRevocationRecord{RevocationEffectiveFrom: &revokedAt},
+			},
+			expectedAPICall: true,
+		},
+		"otherVersionRevoked": {
+			signingTime: now.Add(-time.Hour),
+			profile: &signer.GetSigningProfileOutput{
+				ProfileVersion:   aws.String("OtherVersion"),
+				Status:           types.SigningProfileStatusRevoked,
+				RevocationRecord: &types.SigningProfileRevocationRecord{RevocationEffectiveFrom: &revokedAt},
+			},
+			expectedAPICall: true,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			mockSignerClient := client.NewMockInterface(mockCtrl)
+			if test.expectedAPICall {
+				mockSignerClient.EXPECT().GetSigningProfile(gomock.Any(), gomock.Any()).Return(test.profile, nil)
+			}
+
+			request := mockVerifySigRequest()
+			request.Signature.CriticalAttributes.AuthenticSigningTime = &test.signingTime
+			request.TrustPolicy.SignatureVerification = []plugin.Capability{plugin.CapabilityTrustedIdentityVerifier}
+			request.PluginConfig = map[string]string{configKeySigningTimeCheck: "true", configKeyReasonFormat: reasonFormatCode}
+			if test.clockSkew != "" {
+				request.PluginConfig[configKeyClockSkew] = test.clockSkew
+			}
+			resp, err := New(mockSignerClient, WithClock(func() time.Time { return now })).Verify(context.TODO(), request)
+			assert.NoError(t, err, "Verify() returned error")
+			result := resp.VerificationResults[plugin.CapabilityTrustedIdentityVerifier]
+			if test.expectedReason == "" {
+				assert.True(t, result.Success, "trusted identity check failed: %s", result.Reason)
+				return
+			}
+			assert.Equal(t, plugin.VerificationResult{Success: false, Reason: test.expectedReason}, *result)
+		})
+	}
+}
+
+func TestVerify_SigningTimeSkipsRevocation(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	// GetRevocationStatus isn't called for signatures failing the signing time check
+	mockSignerClient := client.NewMockInterface(mockCtrl)
+
+	now := time.Date(2022, 8, 1, 12, 0, 0, 0, time.UTC)
+	signingTime := now.Add(time.Hour)
+	request := mockVerifySigRequest()
+	request.Signature.CriticalAttributes.AuthenticSigningTime = &signingTime
+	request.TrustPolicy.SignatureVerification = []plugin.Capability{plugin.CapabilityTrustedIdentityVerifier, plugin.CapabilityRevocationCheckVerifier}
+	request.PluginConfig = map[string]string{configKeySigningTimeCheck: "true", configKeyReasonFormat: reasonFormatCode}
+	resp, err := New(mockSignerClient, WithClock(func() time.Time { return now })).Verify(context.TODO(), request)
+	assert.NoError(t, err, "Verify() returned error")
+	assert.False(t, resp.VerificationResults[plugin.CapabilityTrustedIdentityVerifier].Success, "trusted identity check succeeded")
+	assert.Equal(t, plugin.VerificationResult{Success: false, Reason: "[" + codeRevocationCheckSkipped + "] " + reasonRevocationCheckSkippedSigningTime}, *resp.VerificationResults[plugin.CapabilityRevocationCheckVerifier])
+}
+
+func TestVerify_SigningTimeSkipped(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	mockSignerClient := client.NewMockInterface(mockCtrl)
+
+	signingTime := time.Now().Add(time.Hour)
+	request := mockVerifySigRequest()
+	request.Signature.CriticalAttributes.AuthenticSigningTime = &signingTime
+	request.TrustPolicy.SignatureVerification = []plugin.Capability{plugin.CapabilityTrustedIdentityVerifier}
+	request.TrustPolicy.TrustedIdentities = []string{"arn:aws:signer:us-west-2:000000000000:/signing-profiles/OtherProfile"}
+	request.PluginConfig = map[string]string{configKeySigningTimeCheck: "true"}
+	resp, err := New(mockSignerClient).Verify(context.TODO(), request)
+	assert.NoError(t, err, "Verify() returned error")
+	assert.Equal(t, reasonTrustedIdentityFailure, resp.VerificationResults[plugin.CapabilityTrustedIdentityVerifier].Reason)
+}
+
+func TestVerify_SigningTimeInvalidConfig(t *testing.T) {
+	revocationOnly := []plugin.Capability{plugin.CapabilityRevocationCheckVerifier}
+	tests := map[string]struct {
+		pluginConfig   map[string]string
+		capabilities   []plugin.Capability
+		expectedErrMsg string
+	}{
+		"invalidCheck": {
+			pluginConfig:   map[string]string{configKeySigningTimeCheck: "on"},
+			expectedErrMsg: fmt.Sprintf(errMsgInvalidConfigValueFmt, "on", configKeySigningTimeCheck, "true, false"),
+		},
+		"invalidClockSkew": {
+			pluginConfig:   map[string]string{configKeySigningTimeCheck: "true", configKeyClockSkew: "-1m"},
+			expectedErrMsg: fmt.Sprintf(errMsgInvalidConfigDurationFmt, "-1m", configKeyClockSkew),
+		},
+		"signingTimeCheckWithoutTrustedIdentity": {
+			pluginConfig:   map[string]string{configKeySigningTimeCheck: "true"},
+			capabilities:   revocationOnly,
+			expectedErrMsg: fmt.Sprintf(errMsgConfigRequiresCapabilityFmt, configKeySigningTimeCheck, plugin.CapabilityTrustedIdentityVerifier),
+		},
+		"profileStatusCheckWithoutTrustedIdentity": {
+			pluginConfig:   map[string]string{configKeyProfileStatusCheck: "true"},
+			capabilities:   revocationOnly,
+			expectedErrMsg: fmt.Sprintf(errMsgConfigRequiresCapabilityFmt, configKeyProfileStatusCheck, plugin.CapabilityTrustedIdentityVerifier),
+		},
+		"allowedRequestersWithoutTrustedIdentity": {
+			pluginConfig:   map[string]string{configKeyAllowedRequesters: "arn:aws:iam::000000000000:role/Signer"},
+			capabilities:   revocationOnly,
+			expectedErrMsg: fmt.Sprintf(errMsgConfigRequiresCapabilityFmt, configKeyAllowedRequesters, plugin.CapabilityTrustedIdentityVerifier),
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			request := mockVerifySigRequest()
+			request.PluginConfig = test.pluginConfig
+			if test.capabilities != nil {
+				request.TrustPolicy.SignatureVerification = test.capabilities
+			}
+			_, err := New(nil).Verify(context.TODO(), request)
+			assert.Equal(t, test.expectedErrMsg, toPluginError(err, t).Message)
+		})
+	}
+}
@@ -17,24 +17,33 @@ package verifier
 
 import (
 	"context"
+	"crypto/sha256"
 	"crypto/sha512"
 	"crypto/x509"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/aws/arn"
 	"github.com/aws/aws-sdk-go-v2/service/signer"
+	"github.com/aws/aws-signer-notation-plugin/internal/cache"
 	"github.com/aws/aws-signer-notation-plugin/internal/client"
 	"github.com/aws/aws-signer-notation-plugin/internal/logger"
+	"github.com/aws/aws-signer-notation-plugin/internal/partition"
 	"github.com/aws/aws-signer-notation-plugin/internal/slices"
+	"github.com/aws/aws-signer-notation-plugin/internal/tracing"
 
 	"github.com/notaryproject/notation-plugin-framework-go/plugin"
 )
 
 const (
-	wildcardIdentity       = "*"
-	errMsgWildcardIdentity = "The AWSSigner plugin does not support wildcard identity in the trust policy."
+	wildcardIdentity        = "*"
+	errMsgWildcardIdentity  = "The AWSSigner plugin does not support wildcard identity in the trust policy."
+	errMsgIdentityPattern   = "trusted identity %q is not a valid AWS Signer identity pattern: %v."
+	errMsgIdentityPartition = "trusted identity %q is not a valid AWS Signer ARN: %v."
 
 	attrSigningProfileVersion = "com.amazonaws.signer.signingProfileVersion"
 	attrSigningJob            = "com.amazonaws.signer.signingJob"
@@ -43,57 +52,199 @@ const (
 	errMsgCertificateParse = "unable to parse certificates in certificate chain."
 	errMsgAttributeParse   = "unable to parse attribute %q."
 
-	reasonTrustedIdentityFailure    = "Signature publisher doesn't match any trusted identities."
-	reasonTrustedIdentitySuccessFmt = "Signature publisher matched %q trusted identity."
-	reasonNotRevoked                = "Signature is not revoked."
-	reasonRevokedResourceFmt        = "Resource(s) %s have been revoked."
-	reasonRevokedCertificate        = "Certificate(s) have been revoked."
+	reasonTrustedIdentityFailure     = "Signature publisher doesn't match any trusted identities."
+	reasonTrustedIdentitySuccessFmt  = "Signature publisher matched %q trusted identity."
+	reasonTrustedPatternSuccessFmt   = "Signature publisher %q matched %q trusted identity pattern."
+	reasonNotRevoked                 = "Signature is not revoked."
+	reasonRevokedResourceFmt         = "Resource(s) %s have been revoked."
+	reasonRevokedCertificate         = "Certificate(s) have been revoked."
+	reasonRevokedCertificateFmt      = "Certificate at position %d of the certificate chain, with subject %q, serial number %s and SHA-256 fingerprint %s, has been revoked."
+	reasonProfileNotActiveFmt        = "Signing profile %q is not active, its status is %q."
+	reasonProfileVersionNotActiveFmt = "Signing profile version %q is not the active version of the signing profile, the active version is %q."
+	reasonJobRequesterFmt            = "Signing job %q was requested by %q."
+	reasonJobProfileMismatchFmt      = "Signing job %q doesn't belong to signing profile version %q."
+	reasonJobRequesterNotAllowedFmt  = "Signing job %q was requested by %q, which isn't an allowed requester."
 
 	platformNotation = "Notation-OCI-SHA384-ECDSA"
+
+	configKeyRevocationFailureMode = "aws-signer-revocation-failure-mode"
+	revocationFailureModeEnforce   = "enforce"
+	revocationFailureModeWarn      = "warn"
+	revocationFailureModeSkip      = "skip"
+
+	configKeyRevocationCacheTTL         = "aws-signer-revocation-cache-ttl"
+	configKeyRevocationCacheNegativeTTL = "aws-signer-revocation-cache-negative-ttl"
+
+	configKeyRevocationBundle          = "aws-signer-revocation-bundle"
+	configKeyRevocationBundlePublicKey = "aws-signer-revocation-bundle-public-key"
+	configKeyRevocationBundleMaxAge    = "aws-signer-revocation-bundle-max-age"
+
+	configKeyProfileStatusCheck = "aws-signer-profile-status-check"
+	configKeyProvenanceCheck    = "aws-signer-provenance-check"
+	configKeyAllowedRequesters  = "aws-signer-allowed-requesters"
+	configKeySigningTimeCheck   = "aws-signer-signing-time-check"
+	configKeyClockSkew          = "aws-signer-clock-skew"
+
+	errMsgInvalidConfigValueFmt       = "invalid value %q for plugin config %q, supported values are: %s."
+	errMsgInvalidConfigDurationFmt    = "invalid value %q for plugin config %q, expected a non-negative duration such as \"10m\"."
+	errMsgMissingConfigFmt            = "plugin config %q is required when %q is set."
+	errMsgConfigRequiresCapabilityFmt = "plugin config %q requires the %q capability in the trust policy."
+	errMsgRevocationBundleFmt         = "unable to use revocation bundle %q: %v."
+
+	reasonSigningTimeInFutureFmt           = "Authentic signing time %s is later than current time %s plus clock skew tolerance of %s."
+	reasonSigningTimeOutsideCertificateFmt = "Authentic signing time %s is outside the validity period of the signing certificate, from %s to %s."
+	reasonSigningTimeAfterRevocationFmt    = "Authentic signing time %s is not earlier than %s, when signing profile version %q was revoked."
+
+	reasonRevocationCheckFailedFmt          = "%+v. Revocation failure mode %q: signature is treated as revoked."
+	reasonRevocationCheckWarnFmt            = "%+v. Revocation failure mode %q: revocation status is unknown, continuing verification."
+	reasonRevocationCheckSkippedFmt         = "Revocation failure mode %q: revocation check skipped because revocation status couldn't be determined."
+	reasonRevocationCheckSkippedSigningTime = "Revocation check skipped because the authentic signing time check failed."
 )
 
 var verificationCapabilities = []plugin.Capability{
 	plugin.CapabilityTrustedIdentityVerifier,
 	plugin.CapabilityRevocationCheckVerifier}
 
+var revocationFailureModes = []string{
+	revocationFailureModeEnforce,
+	revocationFailureModeWarn,
+	revocationFailureModeSkip}
+
+var booleanConfigValues = []string{"true", "false"}
+
 // Verifier verifies signature generated using AWS Signer.
 type Verifier struct {
-	awssigner client.Interface
+	awssigner       client.Interface
+	revocationCache RevocationCache
+	profiles        *ProfileCache
+	clock           func() time.Time
+}
+
+// RevocationCache caches the revoked entities returned by GetRevocationStatus API.
+type RevocationCache interface {
+	// Get returns the revoked entities cached for key. The second return value is false if entry is not present.
+	Get(key string) ([]string, bool)
+	// Set caches revokedEntities for key.
+	Set(key string, revokedEntities []string) error
+}
+
+// Option configures the Verifier created by New.
+type Option func(*Verifier)
+
+// WithRevocationCache caches the revocation status using the given cache instead of the on-disk revocation cache
+// enabled through plugin config.
+func WithRevocationCache(c RevocationCache) Option {
+	return func(v *Verifier) {
+		v.revocationCache = c
+	}
+}
+
+// WithProfileCache caches the signing profiles looked up by the profile status and signing time checks in the given
+// cache, e.g. to share it between the Verifiers created for the requests of a plugin instance. By default, each
+// Verifier has its own cache.
+func WithProfileCache(c *ProfileCache) Option {
+	return func(v *Verifier) {
+		v.profiles = c
+	}
+}
+
+// WithClock uses the given clock instead of the system clock, e.g. to check the age of the revocation bundle and to
+// reject future-dated signatures.
+func WithClock(clock func() time.Time) Option {
+	return func(v *Verifier) {
+		v.clock = clock
+	}
 }
 
 // New returns Verifier given an AWS Signer client.
-func New(s client.Interface) *Verifier {
-	return &Verifier{awssigner: s}
+func New(s client.Interface, opts ...Option) *Verifier {
+	v := &Verifier{awssigner: s}
+	for _, opt := range opts {
+		opt(v)
+	}
+	if v.profiles == nil {
+		v.profiles = NewProfileCache()
+	}
+	return v
+}
+
+func (v *Verifier) now() time.Time {
+	if v.clock != nil {
+		return v.clock()
+	}
+	return time.Now()
 }
 
 // Verify provides extended verification (including trusted-identity and revocation check)
 // for signatures generated using AWS Signer.
-func (v *Verifier) Verify(ctx context.Context, request *plugin.VerifySignatureRequest) (*plugin.VerifySignatureResponse, error) {
+func (v *Verifier) Verify(ctx context.Context, request *plugin.VerifySignatureRequest) (_ *plugin.VerifySignatureResponse, err error) {
+	ctx, span := tracing.Start(ctx, "VerifySignature")
+	defer func() { tracing.End(span, err) }()
 	log := logger.GetLogger(ctx)
 	log.Debug("validating VerifySignatureRequest")
-	if err := validate(request); err != nil {
+	_, validationSpan := tracing.Start(ctx, "ValidateRequest")
+	err = validate(request)
+	tracing.End(validationSpan, err)
+	if err != nil {
 		log.Debugf("validate VerifySignatureRequest error :%s", err)
 		return nil, err
 	}
-
-	response := plugin.VerifySignatureResponse{
-		VerificationResults: make(map[plugin.Capability]*plugin.VerificationResult),
+	if profileVersionArn, err := getValueAsString(request.Signature.CriticalAttributes.ExtendedAttributes, attrSigningProfileVersion); err == nil {
+		log = log.With(logger.FieldProfileArn, profileVersionArn)
+		ctx = log.UpdateContext(ctx)
+		span.SetAttributes(tracing.ProfileAttributes(profileVersionArn)...)
 	}
+
+	results := make(map[plugin.Capability]*result)
 	if slices.Contains(request.TrustPolicy.SignatureVerification, plugin.CapabilityTrustedIdentityVerifier) {
 		log.Debug("validating trusted identity")
-		if err := validateTrustedIdentity(request, &response); err != nil {
+		if err := validateTrustedIdentity(request, results); err != nil {
 			log.Debugf("validate trusted identity error :%v", err)
 			return nil, err
 		}
-		log.Debugf("verification response: %+v\n", response)
+		if request.PluginConfig[configKeyProfileStatusCheck] == "true" {
+			log.Debug("validating signing profile status")
+			if err := v.validateProfileStatus(ctx, request, results); err != nil {
+				log.Debugf("validate signing profile status error :%v", err)
+				return nil, err
+			}
+		}
+		if isProvenanceCheckEnabled(request.PluginConfig) {
+			log.Debug("validating signing job provenance")
+			if err := v.validateProvenance(ctx, request, results); err != nil {
+				log.Debugf("validate signing job provenance error :%v", err)
+				return nil, err
+			}
+		}
+		if request.PluginConfig[configKeySigningTimeCheck] == "true" {
+			log.Debug("validating authentic signing time")
+			if err := v.validateSigningTime(ctx, request, results); err != nil {
+				log.Debugf("validate authentic signing time error :%v", err)
+				return nil, err
+			}
+		}
+		log.Debugf("trusted identity result: %+v\n", *results[plugin.CapabilityTrustedIdentityVerifier])
 	}
 	if slices.Contains(request.TrustPolicy.SignatureVerification, plugin.CapabilityRevocationCheckVerifier) {
-		log.Debug("validating revocation status")
-		if err := v.validateRevocation(ctx, request, &response); err != nil {
-			log.Debugf("validate revocation status error :%v", err)
-			return nil, err
+		if isSigningTimeFailure(results[plugin.CapabilityTrustedIdentityVerifier]) {
+			// signatures with invalid authentic signing time aren't checked against AWS Signer's revocation status
+			log.Debug("skipping revocation status validation because authentic signing time validation failed")
+			results[plugin.CapabilityRevocationCheckVerifier] = &result{
+				success: false,
+				reason:  reason{Code: codeRevocationCheckSkipped, Message: reasonRevocationCheckSkippedSigningTime},
+			}
+		} else {
+			log.Debug("validating revocation status")
+			if err := v.validateRevocation(ctx, request, results); err != nil {
+				log.Debugf("validate revocation status error :%v", err)
+				return nil, err
+			}
 		}
-		log.Debugf("verification response: %+v\n", response)
+		log.Debugf("revocation check result: %+v\n", *results[plugin.CapabilityRevocationCheckVerifier])
+	}
+
+	response := plugin.VerifySignatureResponse{
+		VerificationResults: toVerificationResults(results, request.PluginConfig),
 	}
 
 	// marking both signing-job ARN and signing-profile-version arn as processed attributes here because the plugin should
@@ -112,6 +263,19 @@ func validate(req *plugin.VerifySignatureRequest) error {
 		return plugin.NewValidationError(errMsgWildcardIdentity)
 	}
 
+	for _, identity := range req.TrustPolicy.TrustedIdentities {
+		if a, ok := isSigningProfileArn(identity); ok {
+			if err := partition.ValidateARN(a, patternWildcard); err != nil {
+				return plugin.NewValidationErrorf(errMsgIdentityPartition, identity, err)
+			}
+			if isIdentityPattern(identity) {
+				if _, err := parseIdentityPattern(identity); err != nil {
+					return plugin.NewValidationErrorf(errMsgIdentityPattern, identity, err)
+				}
+			}
+		}
+	}
+
 	for _, value := range req.TrustPolicy.SignatureVerification {
 		if !pluginCapabilitySupported(value) {
 			return plugin.NewValidationErrorf("'%s' is not a supported plugin capability", value)
@@ -127,10 +291,45 @@ func validate(req *plugin.VerifySignatureRequest) error {
 		return plugin.NewUnsupportedError(fmt.Sprintf("'%s' signing scheme", req.Signature.CriticalAttributes.SigningScheme))
 	}
 
+	if format, ok := req.PluginConfig[configKeyReasonFormat]; ok && !slices.Contains(reasonFormats, format) {
+		return plugin.NewValidationErrorf(errMsgInvalidConfigValueFmt, format, configKeyReasonFormat, strings.Join(reasonFormats, ", "))
+	}
+
+	if mode, ok := req.PluginConfig[configKeyRevocationFailureMode]; ok && !slices.Contains(revocationFailureModes, mode) {
+		return plugin.NewValidationErrorf(errMsgInvalidConfigValueFmt, mode, configKeyRevocationFailureMode, strings.Join(revocationFailureModes, ", "))
+	}
+
+	for _, key := range []string{configKeyProfileStatusCheck, configKeyProvenanceCheck, configKeySigningTimeCheck} {
+		if val, ok := req.PluginConfig[key]; ok && !slices.Contains(booleanConfigValues, val) {
+			return plugin.NewValidationErrorf(errMsgInvalidConfigValueFmt, val, key, strings.Join(booleanConfigValues, ", "))
+		}
+	}
+
+	// the profile status, provenance and signing time checks are part of the trusted identity check
+	if !slices.Contains(req.TrustPolicy.SignatureVerification, plugin.CapabilityTrustedIdentityVerifier) {
+		for _, key := range []string{configKeyProfileStatusCheck, configKeyProvenanceCheck, configKeyAllowedRequesters, configKeySigningTimeCheck} {
+			if val, ok := req.PluginConfig[key]; ok && (key == configKeyAllowedRequesters || val == "true") {
+				return plugin.NewValidationErrorf(errMsgConfigRequiresCapabilityFmt, key, plugin.CapabilityTrustedIdentityVerifier)
+			}
+		}
+	}
+
+	for _, key := range []string{configKeyRevocationCacheTTL, configKeyRevocationCacheNegativeTTL, configKeyRevocationBundleMaxAge, configKeyClockSkew} {
+		if _, err := getDurationConfig(req.PluginConfig, key); err != nil {
+			return err
+		}
+	}
+
+	if _, ok := req.PluginConfig[configKeyRevocationBundle]; ok {
+		if _, ok := req.PluginConfig[configKeyRevocationBundlePublicKey]; !ok {
+			return plugin.NewValidationErrorf(errMsgMissingConfigFmt, configKeyRevocationBundlePublicKey, configKeyRevocationBundle)
+		}
+	}
+
 	return nil
 }
 
-func validateTrustedIdentity(request *plugin.VerifySignatureRequest, response *plugin.VerifySignatureResponse) error {
+func validateTrustedIdentity(request *plugin.VerifySignatureRequest, results map[plugin.Capability]*result) error {
 	signatureIdentity, err := getValueAsString(request.Signature.CriticalAttributes.ExtendedAttributes, attrSigningProfileVersion)
 	if err != nil {
 		return err
@@ -143,14 +342,26 @@ func validateTrustedIdentity(request *plugin.VerifySignatureRequest, response *p
 		}
 	}
 
-	result := &plugin.VerificationResult{
-		Success: false,
-		Reason:  reasonTrustedIdentityFailure,
+	res := &result{
+		success: false,
+		reason:  reason{Code: codeIdentityNotMatched, Message: reasonTrustedIdentityFailure},
 	}
 
 	var profileMatch bool
 	for _, identity := range request.TrustPolicy.TrustedIdentities {
 		if arn, ok := isSigningProfileArn(identity); ok {
+			if isIdentityPattern(identity) {
+				if pattern, err := parseIdentityPattern(identity); err == nil && pattern.matches(signatureIdentity) {
+					res.success = true
+					res.reason = reason{
+						Code:            codeIdentityMatched,
+						Message:         fmt.Sprintf(reasonTrustedPatternSuccessFmt, signatureIdentity, identity),
+						MatchedIdentity: identity,
+					}
+					break
+				}
+				continue
+			}
 			s := strings.Split(arn.Resource, "/")
 			if len(s) == 3 { // if profile arn
 				lastIndex := strings.LastIndex(signatureIdentity, "/")
@@ -163,14 +374,18 @@ func validateTrustedIdentity(request *plugin.VerifySignatureRequest, response *p
 				}
 			}
 			if profileMatch {
-				result.Success = true
-				result.Reason = fmt.Sprintf(reasonTrustedIdentitySuccessFmt, identity)
+				res.success = true
+				res.reason = reason{
+					Code:            codeIdentityMatched,
+					Message:         fmt.Sprintf(reasonTrustedIdentitySuccessFmt, identity),
+					MatchedIdentity: identity,
+				}
 				break
 			}
 		}
 	}
 
-	response.VerificationResults[plugin.CapabilityTrustedIdentityVerifier] = result
+	results[plugin.CapabilityTrustedIdentityVerifier] = res
 	return nil
 }
 
@@ -192,7 +407,7 @@ func getValueAsString(m map[string]interface{}, k string) (string, error) {
 	return "", plugin.NewValidationErrorf(errMsgAttributeParse, k)
 }
 
-func (v *Verifier) validateRevocation(ctx context.Context, request *plugin.VerifySignatureRequest, response *plugin.VerifySignatureResponse) error {
+func (v *Verifier) validateRevocation(ctx context.Context, request *plugin.VerifySignatureRequest, results map[plugin.Capability]*result) error {
 	profileVersionArn, err := getValueAsString(request.Signature.CriticalAttributes.ExtendedAttributes, attrSigningProfileVersion)
 	if err != nil {
 		return err
@@ -216,49 +431,204 @@ func (v *Verifier) validateRevocation(ctx context.Context, request *plugin.Verif
 		SignatureTimestamp: request.Signature.CriticalAttributes.AuthenticSigningTime,
 	}
 
-	result := &plugin.VerificationResult{
-		Success: true,
-		Reason:  reasonNotRevoked,
+	res := &result{
+		success: true,
+		reason:  reason{Code: codeNotRevoked, Message: reasonNotRevoked},
 	}
-	output, err := v.awssigner.GetRevocationStatus(ctx, input)
+	revokedEntities, err := v.getRevokedEntities(ctx, request.PluginConfig, input)
 	if err != nil {
-		result.Success = false
-		result.Reason = fmt.Sprintf("GetRevocationStatus call failed with error: %+v", err)
+		// an invalid, tampered or stale revocation bundle fails verification regardless of the revocation failure mode
+		var pluginErr *plugin.Error
+		if errors.As(err, &pluginErr) {
+			return err
+		}
+		res = getRevocationFailureResult(ctx, getRevocationFailureMode(request.PluginConfig), err)
 	} else {
-		if len(output.RevokedEntities) > 0 {
-			result.Success = false
-			result.Reason = getRevocationResultReason(output.RevokedEntities)
+		if len(revokedEntities) > 0 {
+			res.success = false
+			res.reason = getRevocationResultReason(revokedEntities, certHashes, request.Signature.CertificateChain)
+			for _, cert := range res.RevokedEntities.Certificates {
+				logger.GetLogger(ctx).Debugf("revoked certificate at position %d of the certificate chain. subject: %s, serial number: %s, SHA-256 fingerprint: %s, hash: %s\n",
+					cert.Position, cert.Subject, cert.SerialNumber, cert.Fingerprint, cert.Hash)
+			}
 		}
 	}
 
-	response.VerificationResults[plugin.CapabilityRevocationCheckVerifier] = result
+	results[plugin.CapabilityRevocationCheckVerifier] = res
 	return nil
 }
 
-func getRevocationResultReason(revokedEntities []string) string {
-	var resources string
+// getRevokedEntities evaluates the revocation bundle if it is configured through plugin config, otherwise it calls
+// GetRevocationStatus API, using the on-disk revocation cache if it is enabled through plugin config. Failure to read
+// or write the cache doesn't fail the revocation check, while failure to use the revocation bundle is returned as a
+// validation error.
+func (v *Verifier) getRevokedEntities(ctx context.Context, pluginConfig map[string]string, input *signer.GetRevocationStatusInput) ([]string, error) {
+	log := logger.GetLogger(ctx)
+	if bundlePath, ok := pluginConfig[configKeyRevocationBundle]; ok {
+		log.Debugf("evaluating revocation status offline using revocation bundle %s\n", bundlePath)
+		maxAge := defaultRevocationBundleMaxAge
+		if _, ok := pluginConfig[configKeyRevocationBundleMaxAge]; ok {
+			maxAge, _ = getDurationConfig(pluginConfig, configKeyRevocationBundleMaxAge)
+		}
+		snapshot, err := loadRevocationSnapshot(bundlePath, pluginConfig[configKeyRevocationBundlePublicKey], maxAge, v.now())
+		if err != nil {
+			return nil, plugin.NewValidationErrorf(errMsgRevocationBundleFmt, bundlePath, err)
+		}
+		return snapshot.revokedEntities(aws.ToString(input.ProfileVersionArn), aws.ToString(input.JobArn), input.CertificateHashes, aws.ToTime(input.SignatureTimestamp)), nil
+	}
+
+	revocationCache := v.revocationCache
+	if revocationCache == nil {
+		if c := getRevocationCache(ctx, pluginConfig); c != nil {
+			revocationCache = c
+		}
+	}
+	var cacheKey string
+	if revocationCache != nil {
+		cacheKey = cache.RevocationKey(aws.ToString(input.ProfileVersionArn), aws.ToString(input.JobArn), input.CertificateHashes, aws.ToTime(input.SignatureTimestamp))
+		if revokedEntities, ok := revocationCache.Get(cacheKey); ok {
+			log.Debugf("using cached revocation status: %v\n", revokedEntities)
+			return revokedEntities, nil
+		}
+	}
+
+	revocationCtx, revocationSpan := tracing.Start(ctx, "Signer.GetRevocationStatus", tracing.ProfileAttributes(aws.ToString(input.ProfileVersionArn))...)
+	output, err := v.awssigner.GetRevocationStatus(revocationCtx, input)
+	tracing.End(revocationSpan, err)
+	if err != nil {
+		log.With(logger.FieldRequestID, client.RequestID(err)).Debugf("failed AWS Signer's GetRevocationStatus API call with error: %v\n", err)
+		return nil, fmt.Errorf("GetRevocationStatus call failed with error: %w", err)
+	}
+	log.With(logger.FieldRequestID, client.ResultRequestID(output.ResultMetadata)).Debugf("succeeded AWS Signer's GetRevocationStatus API call. revoked entities: %v\n", output.RevokedEntities)
+
+	if revocationCache != nil {
+		if err := revocationCache.Set(cacheKey, output.RevokedEntities); err != nil {
+			log.Debugf("unable to cache revocation status: %v\n", err)
+		}
+	}
+	return output.RevokedEntities, nil
+}
+
+func getRevocationCache(ctx context.Context, pluginConfig map[string]string) *cache.Revocation {
+	ttl, _ := getDurationConfig(pluginConfig, configKeyRevocationCacheTTL)
+	if ttl <= 0 {
+		return nil
+	}
+	negativeTTL := ttl
+	if _, ok := pluginConfig[configKeyRevocationCacheNegativeTTL]; ok {
+		negativeTTL, _ = getDurationConfig(pluginConfig, configKeyRevocationCacheNegativeTTL)
+	}
+
+	c, err := cache.NewRevocation(ttl, negativeTTL)
+	if err != nil {
+		logger.GetLogger(ctx).Debugf("unable to create revocation cache: %v\n", err)
+		return nil
+	}
+	return c
+}
+
+func getDurationConfig(pluginConfig map[string]string, key string) (time.Duration, error) {
+	val, ok := pluginConfig[key]
+	if !ok {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(val)
+	if err != nil || d < 0 {
+		return 0, plugin.NewValidationErrorf(errMsgInvalidConfigDurationFmt, val, key)
+	}
+	return d, nil
+}
+
+// getRevocationFailureResult returns the revocation check result as per the revocation failure mode when
+// revocation status couldn't be determined.
+func getRevocationFailureResult(ctx context.Context, mode string, err error) *result {
+	switch mode {
+	case revocationFailureModeWarn:
+		logger.GetLogger(ctx).Warnf("ignoring GetRevocationStatus failure as per revocation failure mode %q: %v\n", mode, err)
+		return &result{
+			success: true,
+			reason:  reason{Code: codeRevocationStatusUnknown, Message: fmt.Sprintf(reasonRevocationCheckWarnFmt, err, mode)},
+		}
+	case revocationFailureModeSkip:
+		return &result{
+			success: true,
+			reason:  reason{Code: codeRevocationCheckSkipped, Message: fmt.Sprintf(reasonRevocationCheckSkippedFmt, mode)},
+		}
+	default:
+		return &result{
+			success: false,
+			reason:  reason{Code: codeRevocationCheckFailed, Message: fmt.Sprintf(reasonRevocationCheckFailedFmt, err, mode)},
+		}
+	}
+}
+
+func getRevocationFailureMode(pluginConfig map[string]string) string {
+	if mode, ok := pluginConfig[configKeyRevocationFailureMode]; ok {
+		return mode
+	}
+	return revocationFailureModeEnforce
+}
+
+// getRevocationResultReason returns the reason of the failed revocation check. The revoked certificate hashes are
+// mapped back to the certificates of certChain, whose hashes are certHashes, and reported in the message of the code
+// and json reason formats. The text reason format is kept unchanged for backwards compatibility.
+func getRevocationResultReason(revokedEntities []string, certHashes []string, certChain [][]byte) reason {
+	var resources []string
 	var certRevoked bool
 	for _, resource := range revokedEntities {
 		if strings.HasPrefix(resource, "arn") {
-			if resources == "" {
-				resources += resource
-			} else {
-				resources = resources + ", " + resource
-			}
+			resources = append(resources, resource)
 		} else {
 			certRevoked = true
 		}
 	}
 
-	var reason string
-	if resources != "" {
-		reason = fmt.Sprintf(reasonRevokedResourceFmt, resources)
+	var messages []string
+	var text string
+	if len(resources) > 0 {
+		messages = append(messages, fmt.Sprintf(reasonRevokedResourceFmt, strings.Join(resources, ", ")))
+		text = messages[0]
 	}
+	entities := classifyRevokedEntities(revokedEntities)
 	if certRevoked {
-		reason += reasonRevokedCertificate
+		messages = append(messages, reasonRevokedCertificate)
+		text += reasonRevokedCertificate
+		entities.Certificates = getRevokedCertificates(entities.CertificateHashes, certHashes, certChain)
+		for _, cert := range entities.Certificates {
+			messages = append(messages, fmt.Sprintf(reasonRevokedCertificateFmt, cert.Position, cert.Subject, cert.SerialNumber, cert.Fingerprint))
+		}
 	}
 
-	return reason
+	return reason{
+		Code:            codeRevoked,
+		Message:         strings.Join(messages, " "),
+		RevokedEntities: entities,
+		text:            text,
+	}
+}
+
+// getRevokedCertificates returns the certificates of certChain whose hashes, as computed by hashCertificates, are
+// revoked. The position of the signing certificate is 0.
+func getRevokedCertificates(revokedHashes []string, certHashes []string, certChain [][]byte) []revokedCertificate {
+	var res []revokedCertificate
+	for i, certHash := range certHashes {
+		if !slices.Contains(revokedHashes, certHash) || i >= len(certChain) {
+			continue
+		}
+		cert, err := x509.ParseCertificate(certChain[i])
+		if err != nil {
+			continue
+		}
+		fingerprint := sha256.Sum256(cert.Raw)
+		res = append(res, revokedCertificate{
+			Position:     i,
+			Subject:      cert.Subject.String(),
+			SerialNumber: cert.SerialNumber.Text(16),
+			Fingerprint:  hex.EncodeToString(fingerprint[:]),
+			Hash:         certHash,
+		})
+	}
+	return res
 }
 
 func hashCertificates(certStrings [][]byte) ([]string, error) {
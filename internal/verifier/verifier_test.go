@@ -22,9 +22,14 @@ import (
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-signer-notation-plugin/internal/client"
+	"github.com/aws/aws-signer-notation-plugin/internal/tracing"
 	"github.com/aws/smithy-go"
 	"github.com/notaryproject/notation-plugin-framework-go/plugin"
 	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace/noop"
 
 	"github.com/aws/aws-sdk-go-v2/service/signer"
 	"github.com/golang/mock/gomock"
@@ -54,8 +59,10 @@ h770TLUfa+PzpbIinp2cF/XYVchepCiCJDAdTR1tWKHaqeuW/WQHKso7Z6wyPO24
 d3m5GyGuIRMddbp6zclSRP/I4TCS/0cOru9ATc94PaKWjDOTClYH8ykRZom8OICq
 KCzg3o7lofVNdVFxDM8rrMJ06cY=
 -----END CERTIFICATE-----`
-	testCertificate1Hash = "13a01b7e1de3aee0367615c59f6d001238913e594626d0e3c8784489b15a18fada1c31f39d3ba9318cb673ffd8cd679b"
-	testCertificate2     = `-----BEGIN CERTIFICATE-----
+	testCertificate1Hash        = "13a01b7e1de3aee0367615c59f6d001238913e594626d0e3c8784489b15a18fada1c31f39d3ba9318cb673ffd8cd679b"
+	testCertificate1Fingerprint = "a3f308c62be82c97b0c5423bd614445ff538011bb39255c1049cbfe25fdf8ce5"
+	testCertificate1Serial      = "c1f447e3b0bfacd747344982391650f1"
+	testCertificate2            = `-----BEGIN CERTIFICATE-----
 MIIC7zCCAdegAwIBAgIRAPxhWP65yw1qFSMD39FxuUwwDQYJKoZIhvcNAQELBQAw
 ETEPMA0GA1UECgwGY2hpZW5iMB4XDTE5MTAwNzE4MDIxMVoXDTI5MTAwNzE5MDIx
 MVowETEPMA0GA1UECgwGY2hpZW5iMIIBIjANBgkqhkiG9w0BAQEFAAOCAQ8AMIIB
@@ -73,7 +80,9 @@ zSZee7oqEixEVzis7Ex7mvXBiRdjZBp8cFuRJVKPBgK7SmFkJwyLtd2OLtNehUsh
 Af8fCVvIhr9YxXK+RqiRUhvJDrS9DlKA6dT4KvR41B/a8NLf6PJGyHdSFuvKZr0z
 C+gMfNFGs1L2QLg1+xnoLHIey4tRXYHjpD2b/KALNr4/v+c=
 -----END CERTIFICATE-----`
-	testCertificate2Hash = "ff41924f0940448d7e46b8c327e129813b1442fb17c9b2a86d49edcb00b707c9662f561c8a3e11a592b25061d488f2a3"
+	testCertificate2Hash        = "ff41924f0940448d7e46b8c327e129813b1442fb17c9b2a86d49edcb00b707c9662f561c8a3e11a592b25061d488f2a3"
+	testCertificate2Fingerprint = "6ee85427442c57cbc2064ffda4f36c0c61f520efb8f89d9ddb140303b8be7a71"
+	testCertificate2Serial      = "fc6158feb9cb0d6a152303dfd171b94c"
 )
 
 var testTISuccessReason = fmt.Sprintf(reasonTrustedIdentitySuccessFmt, testProfileArn)
@@ -191,6 +200,10 @@ func TestVerify_RevokedResources(t *testing.T) {
 			revokedResources: []string{testJobArn, testCertificate1},
 			errorMsg:         fmt.Sprintf(reasonRevokedResourceFmt, testJobArn) + reasonRevokedCertificate,
 		},
+		"revokedSigningCertificate": {
+			revokedResources: []string{testCertificate1Hash + testCertificate2Hash},
+			errorMsg:         reasonRevokedCertificate,
+		},
 	}
 
 	for name, test := range tests {
@@ -214,14 +227,161 @@ func TestVerify_RevocationCheckError(t *testing.T) {
 		Code:    "ERROR",
 		Message: "AWSSigner unreachable. 5xx",
 	}
-	mockSignerClient, mockCtrl := getMockClient(nil, nil, &apiError, t)
+	errMsg := "GetRevocationStatus call failed with error: api error ERROR: " + apiError.ErrorMessage()
+	tests := map[string]struct {
+		pluginConfig map[string]string
+		success      bool
+		reason       string
+	}{
+		"defaultMode": {
+			success: false,
+			reason:  fmt.Sprintf(reasonRevocationCheckFailedFmt, errMsg, revocationFailureModeEnforce),
+		},
+		"enforceMode": {
+			pluginConfig: map[string]string{configKeyRevocationFailureMode: revocationFailureModeEnforce},
+			success:      false,
+			reason:       fmt.Sprintf(reasonRevocationCheckFailedFmt, errMsg, revocationFailureModeEnforce),
+		},
+		"warnMode": {
+			pluginConfig: map[string]string{configKeyRevocationFailureMode: revocationFailureModeWarn},
+			success:      true,
+			reason:       fmt.Sprintf(reasonRevocationCheckWarnFmt, errMsg, revocationFailureModeWarn),
+		},
+		"skipMode": {
+			pluginConfig: map[string]string{configKeyRevocationFailureMode: revocationFailureModeSkip},
+			success:      true,
+			reason:       fmt.Sprintf(reasonRevocationCheckSkippedFmt, revocationFailureModeSkip),
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockSignerClient, mockCtrl := getMockClient(nil, nil, &apiError, t)
+			defer mockCtrl.Finish()
+
+			request := mockVerifySigRequest()
+			request.PluginConfig = test.pluginConfig
+			expectedResponse := getVerifySigResponse(true, testTISuccessReason, test.success, test.reason)
+			actualResponse, err := New(mockSignerClient).Verify(context.TODO(), request)
+
+			validateResponse(t, expectedResponse, *actualResponse, err)
+		})
+	}
+}
+
+func TestVerify_Spans(t *testing.T) {
+	tests := map[string]struct {
+		grsOutput *signer.GetRevocationStatusOutput
+		grsErr    error
+		outcomes  map[string]string
+	}{
+		"success": {
+			grsOutput: &signer.GetRevocationStatusOutput{RevokedEntities: []string{}},
+			outcomes: map[string]string{
+				"ValidateRequest":            tracing.OutcomeSuccess,
+				"Signer.GetRevocationStatus": tracing.OutcomeSuccess,
+				"VerifySignature":            tracing.OutcomeSuccess,
+			},
+		},
+		"revocationCheckError": {
+			grsErr: &smithy.GenericAPIError{Code: "ERROR", Message: "AWSSigner unreachable. 5xx"},
+			outcomes: map[string]string{
+				"ValidateRequest":            tracing.OutcomeSuccess,
+				"Signer.GetRevocationStatus": tracing.OutcomeFailure,
+				"VerifySignature":            tracing.OutcomeSuccess,
+			},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			recorder := tracetest.NewSpanRecorder()
+			otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+			defer otel.SetTracerProvider(noop.NewTracerProvider())
+			mockSignerClient, mockCtrl := getMockClient(nil, tc.grsOutput, tc.grsErr, t)
+			defer mockCtrl.Finish()
+
+			_, err := New(mockSignerClient).Verify(context.TODO(), mockVerifySigRequest())
+			assert.NoError(t, err, "unexpected error")
+
+			outcomes := map[string]string{}
+			for _, span := range recorder.Ended() {
+				for _, attr := range span.Attributes() {
+					if attr.Key == tracing.AttrOutcome {
+						outcomes[span.Name()] = attr.Value.AsString()
+					}
+				}
+				if span.Name() == "Signer.GetRevocationStatus" || span.Name() == "VerifySignature" {
+					assert.Contains(t, span.Attributes(), tracing.AttrProfileArn.String(testProfileVersionArn), "profile ARN attribute mismatch")
+					assert.Contains(t, span.Attributes(), tracing.AttrRegion.String("us-west-2"), "region attribute mismatch")
+				}
+			}
+			assert.Equal(t, tc.outcomes, outcomes, "span outcomes mismatch")
+		})
+	}
+}
+
+func TestVerify_RevocationCache(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	pluginConfig := map[string]string{configKeyRevocationCacheTTL: "1h"}
+	revokedGRSOutput := &signer.GetRevocationStatusOutput{RevokedEntities: []string{testJobArn}}
+	mockSignerClient, mockCtrl := getMockClient(nil, revokedGRSOutput, nil, t)
 	defer mockCtrl.Finish()
 
-	revReason := "GetRevocationStatus call failed with error: api error ERROR: " + apiError.ErrorMessage()
-	expectedResponse := getVerifySigResponse(true, testTISuccessReason, false, revReason)
-	actualResponse, err := New(mockSignerClient).Verify(context.TODO(), mockVerifySigRequest())
+	expectedResponse := getVerifySigResponse(true, testTISuccessReason, false, fmt.Sprintf(reasonRevokedResourceFmt, testJobArn))
+	v := New(mockSignerClient)
+	for i := 0; i < 3; i++ {
+		request := mockVerifySigRequest()
+		request.PluginConfig = pluginConfig
+		actualResponse, err := v.Verify(context.TODO(), request)
+		validateResponse(t, expectedResponse, *actualResponse, err)
+	}
+}
 
-	validateResponse(t, expectedResponse, *actualResponse, err)
+type mapRevocationCache map[string][]string
+
+func (c mapRevocationCache) Get(key string) ([]string, bool) {
+	v, ok := c[key]
+	return v, ok
+}
+
+func (c mapRevocationCache) Set(key string, revokedEntities []string) error {
+	c[key] = revokedEntities
+	return nil
+}
+
+func TestVerify_WithRevocationCache(t *testing.T) {
+	revokedGRSOutput := &signer.GetRevocationStatusOutput{RevokedEntities: []string{testJobArn}}
+	mockSignerClient, mockCtrl := getMockClient(nil, revokedGRSOutput, nil, t)
+	defer mockCtrl.Finish()
+
+	revocationCache := mapRevocationCache{}
+	expectedResponse := getVerifySigResponse(true, testTISuccessReason, false, fmt.Sprintf(reasonRevokedResourceFmt, testJobArn))
+	v := New(mockSignerClient, WithRevocationCache(revocationCache))
+	for i := 0; i < 3; i++ {
+		actualResponse, err := v.Verify(context.TODO(), mockVerifySigRequest())
+		validateResponse(t, expectedResponse, *actualResponse, err)
+	}
+	assert.Len(t, revocationCache, 1, "revocation status should be cached")
+}
+
+func TestVerify_RevocationCacheNegativeTTL(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	pluginConfig := map[string]string{
+		configKeyRevocationCacheTTL:         "1h",
+		configKeyRevocationCacheNegativeTTL: "0s",
+	}
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	mockSignerClient := client.NewMockInterface(mockCtrl)
+	mockSignerClient.EXPECT().GetRevocationStatus(gomock.Any(), gomock.Any()).Return(&signer.GetRevocationStatusOutput{}, nil).Times(2)
+
+	expectedResponse := getVerifySigResponse(true, testTISuccessReason, true, reasonNotRevoked)
+	for i := 0; i < 2; i++ {
+		request := mockVerifySigRequest()
+		request.PluginConfig = pluginConfig
+		actualResponse, err := New(mockSignerClient).Verify(context.TODO(), request)
+		validateResponse(t, expectedResponse, *actualResponse, err)
+	}
 }
 
 func TestVerify_MalformedRequest(t *testing.T) {
@@ -255,6 +415,15 @@ func TestVerify_MalformedRequest(t *testing.T) {
 	delete(invalidCritAttrJob.Signature.CriticalAttributes.ExtendedAttributes, attrSigningJob)
 	invalidCritAttrJob.Signature.CriticalAttributes.ExtendedAttributes = nil
 
+	invalidRevocationFailureModeReq := mockVerifySigRequest()
+	invalidRevocationFailureModeReq.PluginConfig = map[string]string{configKeyRevocationFailureMode: "ignore"}
+
+	invalidRevocationCacheTTLReq := mockVerifySigRequest()
+	invalidRevocationCacheTTLReq.PluginConfig = map[string]string{configKeyRevocationCacheTTL: "-1m"}
+
+	missingBundlePublicKeyReq := mockVerifySigRequest()
+	missingBundlePublicKeyReq.PluginConfig = map[string]string{configKeyRevocationBundle: "/path/bundle.json"}
+
 	invalidCritAttrProfile := mockVerifySigRequest()
 	invalidCritAttrProfile.TrustPolicy.SignatureVerification = []plugin.Capability{plugin.CapabilityRevocationCheckVerifier}
 	delete(invalidCritAttrProfile.Signature.CriticalAttributes.ExtendedAttributes, attrSigningProfileVersion)
@@ -304,6 +473,21 @@ func TestVerify_MalformedRequest(t *testing.T) {
 			code:     plugin.ErrorCodeValidation,
 			errorMsg: "unable to parse attribute \"com.amazonaws.signer.signingProfileVersion\".",
 		},
+		"invalidRevocationFailureModeReq": {
+			req:      invalidRevocationFailureModeReq,
+			code:     plugin.ErrorCodeValidation,
+			errorMsg: "invalid value \"ignore\" for plugin config \"aws-signer-revocation-failure-mode\", supported values are: enforce, warn, skip.",
+		},
+		"invalidRevocationCacheTTLReq": {
+			req:      invalidRevocationCacheTTLReq,
+			code:     plugin.ErrorCodeValidation,
+			errorMsg: "invalid value \"-1m\" for plugin config \"aws-signer-revocation-cache-ttl\", expected a non-negative duration such as \"10m\".",
+		},
+		"missingBundlePublicKeyReq": {
+			req:      missingBundlePublicKeyReq,
+			code:     plugin.ErrorCodeValidation,
+			errorMsg: "plugin config \"aws-signer-revocation-bundle-public-key\" is required when \"aws-signer-revocation-bundle\" is set.",
+		},
 		"invalidCritAttrProfile": {
 			req:      invalidCritAttrProfile,
 			code:     plugin.ErrorCodeValidation,
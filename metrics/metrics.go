@@ -0,0 +1,47 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// Package metrics defines the metrics recorded by the AWS Signer plugin when it is used as a library with
+// notation-go. A Recorder is passed to the plugin using [github.com/aws/aws-signer-notation-plugin/plugin.WithMetrics],
+// and the [github.com/aws/aws-signer-notation-plugin/metrics/prometheus] module provides a Prometheus Recorder.
+package metrics
+
+import "time"
+
+// AWS Signer API operations recorded through Recorder.ObserveAPICall.
+const (
+	OperationSignPayload         = "SignPayload"
+	OperationGetRevocationStatus = "GetRevocationStatus"
+	OperationGetSigningProfile   = "GetSigningProfile"
+	OperationDescribeSigningJob  = "DescribeSigningJob"
+)
+
+// Recorder records the metrics of the AWS Signer plugin. Implementations must be safe for concurrent use.
+type Recorder interface {
+	// ObserveAPICall records an AWS Signer API call along with its latency. errorCode is empty if the call
+	// succeeded, otherwise it is the plugin error code the failure is reported with, e.g. THROTTLED or ACCESS_DENIED.
+	ObserveAPICall(operation string, duration time.Duration, errorCode string)
+
+	// ObserveVerification records the result of a verification capability, e.g.
+	// SIGNATURE_VERIFIER.REVOCATION_CHECK, performed while verifying a signature.
+	ObserveVerification(capability string, success bool)
+}
+
+// Nop is a Recorder which doesn't record anything. It is used when no Recorder is configured.
+type Nop struct{}
+
+// ObserveAPICall does nothing.
+func (Nop) ObserveAPICall(string, time.Duration, string) {}
+
+// ObserveVerification does nothing.
+func (Nop) ObserveVerification(string, bool) {}
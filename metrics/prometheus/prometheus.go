@@ -0,0 +1,103 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// Package prometheus provides a [metrics.Recorder] exposing the metrics of the AWS Signer plugin as Prometheus
+// metrics:
+//
+//   - aws_signer_notation_plugin_api_call_duration_seconds histogram of AWS Signer API call latency by operation
+//     and outcome.
+//   - aws_signer_notation_plugin_api_call_errors_total counter of failed AWS Signer API calls by operation and
+//     error code.
+//   - aws_signer_notation_plugin_verification_results_total counter of verification results by capability and
+//     outcome.
+package prometheus
+
+import (
+	"time"
+
+	"github.com/aws/aws-signer-notation-plugin/metrics"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	namespace = "aws_signer_notation_plugin"
+
+	labelOperation  = "operation"
+	labelOutcome    = "outcome"
+	labelErrorCode  = "error_code"
+	labelCapability = "capability"
+
+	outcomeSuccess = "success"
+	outcomeFailure = "failure"
+)
+
+// Recorder is a metrics.Recorder backed by Prometheus collectors.
+type Recorder struct {
+	apiCallDuration     *prometheus.HistogramVec
+	apiCallErrors       *prometheus.CounterVec
+	verificationResults *prometheus.CounterVec
+}
+
+var _ metrics.Recorder = (*Recorder)(nil)
+
+// NewRecorder creates a Recorder and registers its collectors with the given registerer.
+func NewRecorder(registerer prometheus.Registerer) (*Recorder, error) {
+	r := &Recorder{
+		apiCallDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "api_call_duration_seconds",
+			Help:      "Latency of AWS Signer API calls.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{labelOperation, labelOutcome}),
+		apiCallErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "api_call_errors_total",
+			Help:      "Number of failed AWS Signer API calls.",
+		}, []string{labelOperation, labelErrorCode}),
+		verificationResults: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "verification_results_total",
+			Help:      "Number of verification results.",
+		}, []string{labelCapability, labelOutcome}),
+	}
+	collectors := []prometheus.Collector{r.apiCallDuration, r.apiCallErrors, r.verificationResults}
+	for i, c := range collectors {
+		if err := registerer.Register(c); err != nil {
+			for _, registered := range collectors[:i] {
+				registerer.Unregister(registered)
+			}
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+// ObserveAPICall records the latency of the AWS Signer API call, and counts it as an error if errorCode isn't empty.
+func (r *Recorder) ObserveAPICall(operation string, duration time.Duration, errorCode string) {
+	outcome := outcomeSuccess
+	if errorCode != "" {
+		outcome = outcomeFailure
+		r.apiCallErrors.WithLabelValues(operation, errorCode).Inc()
+	}
+	r.apiCallDuration.WithLabelValues(operation, outcome).Observe(duration.Seconds())
+}
+
+// ObserveVerification counts the result of the verification capability.
+func (r *Recorder) ObserveVerification(capability string, success bool) {
+	outcome := outcomeSuccess
+	if !success {
+		outcome = outcomeFailure
+	}
+	r.verificationResults.WithLabelValues(capability, outcome).Inc()
+}
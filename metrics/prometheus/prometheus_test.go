@@ -0,0 +1,63 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package prometheus
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecorder(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	recorder, err := NewRecorder(registry)
+	if err != nil {
+		t.Fatalf("NewRecorder returned error: %v", err)
+	}
+
+	recorder.ObserveAPICall("SignPayload", 200*time.Millisecond, "")
+	recorder.ObserveAPICall("SignPayload", 20*time.Millisecond, "THROTTLED")
+	recorder.ObserveAPICall("GetRevocationStatus", 100*time.Millisecond, "")
+	recorder.ObserveVerification("SIGNATURE_VERIFIER.REVOCATION_CHECK", true)
+	recorder.ObserveVerification("SIGNATURE_VERIFIER.REVOCATION_CHECK", false)
+	recorder.ObserveVerification("SIGNATURE_VERIFIER.TRUSTED_IDENTITY", true)
+
+	assert.Equal(t, 3, testutil.CollectAndCount(recorder.apiCallDuration), "API call duration series mismatch")
+	expected := `
+# HELP aws_signer_notation_plugin_api_call_errors_total Number of failed AWS Signer API calls.
+# TYPE aws_signer_notation_plugin_api_call_errors_total counter
+aws_signer_notation_plugin_api_call_errors_total{error_code="THROTTLED",operation="SignPayload"} 1
+# HELP aws_signer_notation_plugin_verification_results_total Number of verification results.
+# TYPE aws_signer_notation_plugin_verification_results_total counter
+aws_signer_notation_plugin_verification_results_total{capability="SIGNATURE_VERIFIER.REVOCATION_CHECK",outcome="failure"} 1
+aws_signer_notation_plugin_verification_results_total{capability="SIGNATURE_VERIFIER.REVOCATION_CHECK",outcome="success"} 1
+aws_signer_notation_plugin_verification_results_total{capability="SIGNATURE_VERIFIER.TRUSTED_IDENTITY",outcome="success"} 1
+`
+	err = testutil.GatherAndCompare(registry, strings.NewReader(expected),
+		"aws_signer_notation_plugin_api_call_errors_total", "aws_signer_notation_plugin_verification_results_total")
+	assert.NoError(t, err, "metrics mismatch")
+}
+
+func TestNewRecorder_AlreadyRegistered(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	if _, err := NewRecorder(registry); err != nil {
+		t.Fatalf("NewRecorder returned error: %v", err)
+	}
+	_, err := NewRecorder(registry)
+	assert.Error(t, err, "expected error not found")
+}
@@ -0,0 +1,105 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package plugin
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/aws/aws-signer-notation-plugin/metrics"
+
+	"github.com/aws/aws-sdk-go-v2/service/signer"
+	"github.com/notaryproject/notation-plugin-framework-go/log"
+)
+
+// SignerAPI is the subset of the AWS Signer API used by the plugin. It is implemented by *signer.Client of
+// github.com/aws/aws-sdk-go-v2/service/signer.
+type SignerAPI interface {
+	SignPayload(ctx context.Context, params *signer.SignPayloadInput, optFns ...func(*signer.Options)) (*signer.SignPayloadOutput, error)
+	GetRevocationStatus(ctx context.Context, params *signer.GetRevocationStatusInput, optFns ...func(*signer.Options)) (*signer.GetRevocationStatusOutput, error)
+	GetSigningProfile(ctx context.Context, params *signer.GetSigningProfileInput, optFns ...func(*signer.Options)) (*signer.GetSigningProfileOutput, error)
+	DescribeSigningJob(ctx context.Context, params *signer.DescribeSigningJobInput, optFns ...func(*signer.Options)) (*signer.DescribeSigningJobOutput, error)
+}
+
+// RevocationCache caches the revoked entities returned by GetRevocationStatus API. Keys are opaque strings derived
+// from the GetRevocationStatus API input. Implementations must be safe for concurrent use.
+type RevocationCache interface {
+	// Get returns the revoked entities cached for key. The second return value is false if entry is not present.
+	Get(key string) ([]string, bool)
+	// Set caches revokedEntities for key.
+	Set(key string, revokedEntities []string) error
+}
+
+// Option configures the AWSSignerPlugin created by New or NewAWSSigner.
+type Option func(*AWSSignerPlugin)
+
+// WithSignerAPI uses the given AWS Signer API client for all requests. By default, clients are created from the
+// AWS region, credentials, endpoints and retry config in the plugin config of the requests.
+func WithSignerAPI(s SignerAPI) Option {
+	return func(sp *AWSSignerPlugin) {
+		sp.awssigner = s
+	}
+}
+
+// WithLogger forwards the plugin logs to the given logger. Credentials, payloads, signatures and annotations are
+// redacted from the forwarded logs.
+func WithLogger(l log.Logger) Option {
+	return func(sp *AWSSignerPlugin) {
+		sp.logger = l
+	}
+}
+
+// WithRevocationCache caches the revocation status using the given cache, instead of the on-disk revocation cache
+// enabled through aws-signer-revocation-cache-ttl plugin config.
+func WithRevocationCache(c RevocationCache) Option {
+	return func(sp *AWSSignerPlugin) {
+		sp.revocationCache = c
+	}
+}
+
+// WithClock uses the given clock instead of the system clock, e.g. to compute the signature expiry, to check the
+// age of the revocation bundle and to reject future-dated signatures.
+func WithClock(clock func() time.Time) Option {
+	return func(sp *AWSSignerPlugin) {
+		sp.clock = clock
+	}
+}
+
+// WithDefaultPluginConfig uses the given plugin config entries for the keys not set in the plugin config of the
+// request.
+func WithDefaultPluginConfig(pluginConfig map[string]string) Option {
+	return func(sp *AWSSignerPlugin) {
+		sp.defaultPluginConfig = pluginConfig
+	}
+}
+
+// WithHTTPClient uses the given HTTP client for the AWS API calls of the AWS Signer API client created from the
+// plugin config. It has no effect when WithSignerAPI is used.
+func WithHTTPClient(c *http.Client) Option {
+	return func(sp *AWSSignerPlugin) {
+		sp.httpClient = c
+	}
+}
+
+// WithMetrics records the AWS Signer API calls and the verification results using the given metrics.Recorder.
+// Nothing is recorded by default, or when recorder is nil.
+func WithMetrics(recorder metrics.Recorder) Option {
+	return func(sp *AWSSignerPlugin) {
+		if recorder == nil {
+			recorder = metrics.Nop{}
+		}
+		sp.metrics = recorder
+	}
+}
@@ -17,11 +17,19 @@ package plugin
 
 import (
 	"context"
+	"net/http"
+	"sync"
+	"time"
 
 	"github.com/aws/aws-signer-notation-plugin/internal/client"
+	"github.com/aws/aws-signer-notation-plugin/internal/logger"
 	"github.com/aws/aws-signer-notation-plugin/internal/signer"
 	"github.com/aws/aws-signer-notation-plugin/internal/verifier"
 	"github.com/aws/aws-signer-notation-plugin/internal/version"
+	"github.com/aws/aws-signer-notation-plugin/metrics"
+
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/notaryproject/notation-plugin-framework-go/log"
 	"github.com/notaryproject/notation-plugin-framework-go/plugin"
 )
 
@@ -29,12 +37,36 @@ const Name = "com.amazonaws.signer.notation.plugin"
 
 // AWSSignerPlugin provides functionality for signing and verification in accordance with the NotaryProject AWSSignerPlugin contract.
 type AWSSignerPlugin struct {
-	awssigner client.Interface
+	mu                  sync.Mutex
+	awssigner           client.Interface
+	clients             map[string]client.Interface
+	logger              log.Logger
+	revocationCache     RevocationCache
+	profiles            *verifier.ProfileCache
+	clock               func() time.Time
+	defaultPluginConfig map[string]string
+	httpClient          *http.Client
+	metrics             metrics.Recorder
+}
+
+// New creates new AWSSignerPlugin configured by the given options. Unless WithSignerAPI is used, AWS Signer API
+// clients are created from the plugin config of the requests, and reused by requests with the same AWS region,
+// credentials, endpoints and retry config.
+func New(opts ...Option) *AWSSignerPlugin {
+	sp := &AWSSignerPlugin{metrics: metrics.Nop{}}
+	for _, opt := range opts {
+		opt(sp)
+	}
+	if sp.awssigner != nil {
+		sp.awssigner = client.WithMetrics(sp.awssigner, sp.metrics)
+	}
+	return sp
 }
 
-// NewAWSSigner creates new AWSSignerPlugin
-func NewAWSSigner(s client.Interface) *AWSSignerPlugin {
-	return &AWSSignerPlugin{awssigner: s}
+// NewAWSSigner creates new AWSSignerPlugin using the given AWS Signer API client. It is equivalent to
+// New(WithSignerAPI(s), opts...).
+func NewAWSSigner(s client.Interface, opts ...Option) *AWSSignerPlugin {
+	return New(append([]Option{WithSignerAPI(s)}, opts...)...)
 }
 
 // NewAWSSignerForCLI creates a new AWSSignerPlugin and is intended solely for generating executables.
@@ -50,11 +82,27 @@ func (sp *AWSSignerPlugin) VerifySignature(ctx context.Context, req *plugin.Veri
 	if err := req.Validate(); err != nil {
 		return nil, err
 	}
-	if err := sp.setSignerClientIfNotPresent(ctx, req.PluginConfig); err != nil {
+	ctx = sp.withOperation(ctx, plugin.CommandVerifySignature)
+	if len(sp.defaultPluginConfig) > 0 {
+		r := *req
+		r.PluginConfig = sp.withDefaultPluginConfig(req.PluginConfig)
+		req = &r
+	}
+	awssigner, err := sp.getSignerClient(ctx, req.PluginConfig)
+	if err != nil {
 		return nil, err
 	}
 
-	return verifier.New(sp.awssigner).Verify(ctx, req)
+	res, err := sp.newVerifier(awssigner).Verify(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if sp.metrics != nil {
+		for capability, result := range res.VerificationResults {
+			sp.metrics.ObserveVerification(string(capability), result.Success)
+		}
+	}
+	return res, nil
 }
 
 // GetMetadata returns the metadata information of the plugin.
@@ -73,9 +121,26 @@ func (sp *AWSSignerPlugin) GetMetadata(_ context.Context, _ *plugin.GetMetadataR
 	}, nil
 }
 
-// DescribeKey describes the key being used for signing. This method is not supported by AWS Signer's plugin.
-func (sp *AWSSignerPlugin) DescribeKey(_ context.Context, _ *plugin.DescribeKeyRequest) (*plugin.DescribeKeyResponse, error) {
-	return nil, plugin.NewUnsupportedError("DescribeKey operation")
+// DescribeKey describes the key being used for signing by calling AWS Signer.
+// The plugin doesn't declare the SIGNATURE_GENERATOR.RAW capability, so this method is intended for library usage.
+func (sp *AWSSignerPlugin) DescribeKey(ctx context.Context, req *plugin.DescribeKeyRequest) (*plugin.DescribeKeyResponse, error) {
+	if req == nil {
+		return nil, plugin.NewValidationError("describeKey request is nil")
+	}
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	ctx = sp.withOperation(ctx, plugin.CommandDescribeKey)
+	if len(sp.defaultPluginConfig) > 0 {
+		r := *req
+		r.PluginConfig = sp.withDefaultPluginConfig(req.PluginConfig)
+		req = &r
+	}
+	awssigner, err := sp.getSignerClient(ctx, req.PluginConfig)
+	if err != nil {
+		return nil, err
+	}
+	return sp.newSigner(awssigner).DescribeKey(ctx, req)
 }
 
 // GenerateSignature generates the raw signature. This method is not supported by AWS Signer's plugin.
@@ -91,20 +156,93 @@ func (sp *AWSSignerPlugin) GenerateEnvelope(ctx context.Context, req *plugin.Gen
 	if err := req.Validate(); err != nil {
 		return nil, err
 	}
-	if err := sp.setSignerClientIfNotPresent(ctx, req.PluginConfig); err != nil {
+	ctx = sp.withOperation(ctx, plugin.CommandGenerateEnvelope)
+	if len(sp.defaultPluginConfig) > 0 {
+		r := *req
+		r.PluginConfig = sp.withDefaultPluginConfig(req.PluginConfig)
+		req = &r
+	}
+	awssigner, err := sp.getSignerClient(ctx, req.PluginConfig)
+	if err != nil {
 		return nil, err
 	}
 
-	return signer.New(sp.awssigner).GenerateEnvelope(ctx, req)
+	return sp.newSigner(awssigner).GenerateEnvelope(ctx, req)
 }
 
-func (sp *AWSSignerPlugin) setSignerClientIfNotPresent(ctx context.Context, plConfig map[string]string) error {
-	if sp.awssigner == nil {
-		s, err := client.NewAWSSigner(ctx, plConfig)
-		if err != nil {
-			return err
-		}
-		sp.awssigner = s
+// withOperation returns context whose logger attaches the plugin operation to log entries. If WithLogger is used,
+// the log entries are forwarded to that logger.
+func (sp *AWSSignerPlugin) withOperation(ctx context.Context, command plugin.Command) context.Context {
+	if sp.logger != nil {
+		ctx = logger.Forward(sp.logger).UpdateContext(ctx)
+	}
+	return logger.GetLogger(ctx).With(logger.FieldOperation, string(command)).UpdateContext(ctx)
+}
+
+// withDefaultPluginConfig returns a copy of plConfig with the default plugin config entries for the keys it doesn't set.
+func (sp *AWSSignerPlugin) withDefaultPluginConfig(plConfig map[string]string) map[string]string {
+	merged := make(map[string]string, len(sp.defaultPluginConfig)+len(plConfig))
+	for k, v := range sp.defaultPluginConfig {
+		merged[k] = v
+	}
+	for k, v := range plConfig {
+		merged[k] = v
+	}
+	return merged
+}
+
+func (sp *AWSSignerPlugin) newSigner(awssigner client.Interface) *signer.Signer {
+	var opts []signer.Option
+	if sp.clock != nil {
+		opts = append(opts, signer.WithClock(sp.clock))
+	}
+	return signer.New(awssigner, opts...)
+}
+
+func (sp *AWSSignerPlugin) newVerifier(awssigner client.Interface) *verifier.Verifier {
+	sp.mu.Lock()
+	if sp.profiles == nil {
+		sp.profiles = verifier.NewProfileCache()
+	}
+	opts := []verifier.Option{verifier.WithProfileCache(sp.profiles)}
+	sp.mu.Unlock()
+	if sp.revocationCache != nil {
+		opts = append(opts, verifier.WithRevocationCache(sp.revocationCache))
+	}
+	if sp.clock != nil {
+		opts = append(opts, verifier.WithClock(sp.clock))
+	}
+	return verifier.New(awssigner, opts...)
+}
+
+// getSignerClient returns the AWS Signer API client set through WithSignerAPI, otherwise the client created from
+// plConfig, which is reused for plugin configs with the same client.ConfigKey.
+func (sp *AWSSignerPlugin) getSignerClient(ctx context.Context, plConfig map[string]string) (client.Interface, error) {
+	sp.mu.Lock()
+	defer sp.mu.Unlock()
+	if sp.awssigner != nil {
+		return sp.awssigner, nil
+	}
+
+	key := client.ConfigKey(plConfig)
+	if s, ok := sp.clients[key]; ok {
+		return s, nil
+	}
+	var optFns []func(*config.LoadOptions) error
+	if sp.httpClient != nil {
+		optFns = append(optFns, config.WithHTTPClient(sp.httpClient))
+	}
+	s, err := client.NewAWSSigner(ctx, plConfig, optFns...)
+	if err != nil {
+		return nil, err
+	}
+	var awssigner client.Interface = s
+	if sp.metrics != nil {
+		awssigner = client.WithMetrics(s, sp.metrics)
+	}
+	if sp.clients == nil {
+		sp.clients = make(map[string]client.Interface)
 	}
-	return nil
+	sp.clients[key] = awssigner
+	return awssigner, nil
 }
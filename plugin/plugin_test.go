@@ -20,9 +20,14 @@ import (
 	"testing"
 	"time"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/signer"
+	"github.com/aws/aws-sdk-go-v2/service/signer/types"
 	"github.com/aws/aws-signer-notation-plugin/internal/client"
+	"github.com/aws/aws-signer-notation-plugin/metrics"
+	"github.com/aws/smithy-go"
 	"github.com/golang/mock/gomock"
+	"github.com/notaryproject/notation-plugin-framework-go/log"
 	"github.com/notaryproject/notation-plugin-framework-go/plugin"
 	"github.com/stretchr/testify/assert"
 )
@@ -116,6 +121,159 @@ func TestGenerateEnvelope_ValidationError(t *testing.T) {
 	}
 }
 
+type testRecorder struct {
+	apiCalls      map[string]string
+	verifications map[string]bool
+}
+
+func (r *testRecorder) ObserveAPICall(operation string, _ time.Duration, errorCode string) {
+	r.apiCalls[operation] = errorCode
+}
+
+func (r *testRecorder) ObserveVerification(capability string, success bool) {
+	r.verifications[capability] = success
+}
+
+func TestWithMetrics(t *testing.T) {
+	recorder := &testRecorder{apiCalls: map[string]string{}, verifications: map[string]bool{}}
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	mockSignerClient := client.NewMockInterface(mockCtrl)
+	mockSignerClient.EXPECT().GetRevocationStatus(gomock.Any(), gomock.Any()).Return(&signer.GetRevocationStatusOutput{RevokedEntities: []string{testJobArn}}, nil)
+	mockSignerClient.EXPECT().SignPayload(gomock.Any(), gomock.Any()).Return(nil, &smithy.GenericAPIError{Code: "ThrottlingException", Message: "Rate exceeded"})
+	awsSignerPlugin := NewAWSSigner(mockSignerClient, WithMetrics(recorder))
+
+	verifyReq, _ := getVerifySignatureRequestResponse()
+	_, err := awsSignerPlugin.VerifySignature(context.TODO(), verifyReq)
+	assert.NoError(t, err, "VerifySignature() returned error")
+	signReq, _ := getGenerateEnvRequestResponse()
+	_, err = awsSignerPlugin.GenerateEnvelope(context.TODO(), signReq)
+	assert.Error(t, err, "GenerateEnvelope() expected error but not found")
+
+	assert.Equal(t, map[string]string{
+		metrics.OperationGetRevocationStatus: "",
+		metrics.OperationSignPayload:         string(plugin.ErrorCodeThrottled),
+	}, recorder.apiCalls, "API call metrics mismatch")
+	assert.Equal(t, map[string]bool{
+		string(plugin.CapabilityTrustedIdentityVerifier): true,
+		string(plugin.CapabilityRevocationCheckVerifier): false,
+	}, recorder.verifications, "verification metrics mismatch")
+}
+
+func TestWithMetrics_Nil(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	mockSignerClient := client.NewMockInterface(mockCtrl)
+	mockSignerClient.EXPECT().GetRevocationStatus(gomock.Any(), gomock.Any()).Return(&signer.GetRevocationStatusOutput{}, nil)
+	awsSignerPlugin := NewAWSSigner(mockSignerClient, WithMetrics(nil))
+
+	req, expectedResp := getVerifySignatureRequestResponse()
+	resp, err := awsSignerPlugin.VerifySignature(context.TODO(), req)
+	assert.NoError(t, err, "VerifySignature() returned error")
+	assert.Equal(t, expectedResp, resp, "VerifySignatureResponse mismatch")
+}
+
+type mapRevocationCache map[string][]string
+
+func (c mapRevocationCache) Get(key string) ([]string, bool) {
+	v, ok := c[key]
+	return v, ok
+}
+
+func (c mapRevocationCache) Set(key string, revokedEntities []string) error {
+	c[key] = revokedEntities
+	return nil
+}
+
+// captureLogger records the forwarded debug log entries. The plugin only forwards to Debug, Info, Warn and Error.
+type captureLogger struct {
+	log.Logger
+	entries []string
+}
+
+func (c *captureLogger) Debug(args ...interface{}) {
+	c.entries = append(c.entries, fmt.Sprint(args...))
+}
+
+func TestNew_Options(t *testing.T) {
+	t.Run("WithRevocationCacheAndLogger", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+		mockSignerClient := client.NewMockInterface(mockCtrl)
+		mockSignerClient.EXPECT().GetRevocationStatus(gomock.Any(), gomock.Any()).Return(&signer.GetRevocationStatusOutput{}, nil).Times(1)
+		revocationCache := mapRevocationCache{}
+		sink := &captureLogger{}
+		awsSignerPlugin := New(WithSignerAPI(mockSignerClient), WithRevocationCache(revocationCache), WithLogger(sink))
+
+		req, expectedResp := getVerifySignatureRequestResponse()
+		for i := 0; i < 2; i++ {
+			resp, err := awsSignerPlugin.VerifySignature(context.TODO(), req)
+			assert.NoError(t, err, "VerifySignature() returned error")
+			assert.Equal(t, expectedResp, resp, "VerifySignatureResponse mismatch")
+		}
+		assert.Len(t, revocationCache, 1, "revocation status should be cached")
+		assert.Contains(t, sink.entries, "using cached revocation status: []", "logs should be forwarded")
+	})
+
+	t.Run("WithDefaultPluginConfig", func(t *testing.T) {
+		t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+		mockSignerClient := client.NewMockInterface(mockCtrl)
+		mockSignerClient.EXPECT().GetRevocationStatus(gomock.Any(), gomock.Any()).Return(&signer.GetRevocationStatusOutput{}, nil).Times(1)
+		awsSignerPlugin := New(WithSignerAPI(mockSignerClient), WithDefaultPluginConfig(map[string]string{"aws-signer-revocation-cache-ttl": "1h"}))
+
+		req, _ := getVerifySignatureRequestResponse()
+		for i := 0; i < 2; i++ {
+			_, err := awsSignerPlugin.VerifySignature(context.TODO(), req)
+			assert.NoError(t, err, "VerifySignature() returned error")
+			assert.Nil(t, req.PluginConfig, "request plugin config should not be modified")
+		}
+	})
+
+	t.Run("WithClock", func(t *testing.T) {
+		now := time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+		mockSignerClient := client.NewMockInterface(mockCtrl)
+		mockSignerClient.EXPECT().GetSigningProfile(gomock.Any(), gomock.Any()).Return(&signer.GetSigningProfileOutput{
+			SignatureValidityPeriod: &types.SignatureValidityPeriod{Type: types.ValidityTypeDays, Value: 1},
+		}, nil)
+		mockSignerClient.EXPECT().SignPayload(gomock.Any(), gomock.Any()).Return(&signer.SignPayloadOutput{Signature: []byte("sigEnv")}, nil)
+		awsSignerPlugin := New(WithSignerAPI(mockSignerClient), WithClock(func() time.Time { return now }))
+
+		req, _ := getGenerateEnvRequestResponse()
+		req.ExpiryDurationInSeconds = 86400
+		resp, err := awsSignerPlugin.GenerateEnvelope(context.TODO(), req)
+		assert.NoError(t, err, "GenerateEnvelope() returned error")
+		assert.Equal(t, "2024-02-01T00:00:00Z", resp.Annotations["com.amazonaws.signer.signatureExpiry"], "signature expiry mismatch")
+	})
+}
+
+func TestVerifySignature_ProfileCache(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	mockSignerClient := client.NewMockInterface(mockCtrl)
+	mockSignerClient.EXPECT().GetRevocationStatus(gomock.Any(), gomock.Any()).Return(&signer.GetRevocationStatusOutput{}, nil).AnyTimes()
+	// called once by each plugin instance
+	mockSignerClient.EXPECT().GetSigningProfile(gomock.Any(), gomock.Any()).Return(&signer.GetSigningProfileOutput{Status: types.SigningProfileStatusActive, ProfileVersion: aws.String("OF8IVUsPJq")}, nil).Times(2)
+
+	for _, awsSignerPlugin := range []*AWSSignerPlugin{NewAWSSigner(mockSignerClient), NewAWSSigner(mockSignerClient)} {
+		for i := 0; i < 2; i++ {
+			req, _ := getVerifySignatureRequestResponse()
+			req.PluginConfig = map[string]string{"aws-signer-profile-status-check": "true"}
+			resp, err := awsSignerPlugin.VerifySignature(context.TODO(), req)
+			assert.NoError(t, err, "VerifySignature() returned error")
+			assert.True(t, resp.VerificationResults[plugin.CapabilityTrustedIdentityVerifier].Success, "trusted identity check failed")
+		}
+	}
+}
+
+func TestWithDefaultPluginConfig(t *testing.T) {
+	sp := New(WithDefaultPluginConfig(map[string]string{"key1": "default", "key2": "default"}))
+	assert.Equal(t, map[string]string{"key1": "request", "key2": "default"}, sp.withDefaultPluginConfig(map[string]string{"key1": "request"}))
+}
+
 func TestGetMetadata(t *testing.T) {
 	resp, err := NewAWSSignerForCLI().GetMetadata(context.TODO(), nil)
 	assert.NoError(t, err, "GetMetadata() returned error")
@@ -131,15 +289,55 @@ func TestGenerateSignature_Error(t *testing.T) {
 	assert.Error(t, err, "expected UnsupportedError but not found")
 }
 
-func TestDescribeKey_Error(t *testing.T) {
-	_, err := NewAWSSignerForCLI().DescribeKey(context.TODO(), nil)
-	assert.Error(t, err, "expected UnsupportedError but not found")
+func TestDescribeKey(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	mockSignerClient := client.NewMockInterface(mockCtrl)
+	mockSignerClient.EXPECT().GetSigningProfile(gomock.Any(), gomock.Any()).Return(&signer.GetSigningProfileOutput{PlatformId: aws.String("Notation-OCI-SHA384-ECDSA")}, nil)
+
+	req := &plugin.DescribeKeyRequest{
+		ContractVersion: plugin.ContractVersion,
+		KeyID:           testProfileArn,
+	}
+	resp, err := NewAWSSigner(mockSignerClient).DescribeKey(context.TODO(), req)
+	assert.NoError(t, err, "DescribeKey() returned error")
+	assert.Equal(t, &plugin.DescribeKeyResponse{KeyID: testProfileArn, KeySpec: plugin.KeySpecEC384}, resp, "DescribeKeyResponse mismatch")
 }
 
-func TestGetSignerClientIfNotPresent(t *testing.T) {
+func TestDescribeKey_ValidationError(t *testing.T) {
+	tests := map[string]*plugin.DescribeKeyRequest{
+		"nilRequest":     nil,
+		"invalidRequest": {ContractVersion: ""},
+	}
+	for name, req := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := NewAWSSignerForCLI().DescribeKey(context.TODO(), req)
+			assert.Error(t, err, "DescribeKey() expected error but not found")
+		})
+	}
+}
+
+func TestGetSignerClient(t *testing.T) {
 	signerPlugin := AWSSignerPlugin{}
-	err := signerPlugin.setSignerClientIfNotPresent(context.TODO(), nil)
+	usWest2, err := signerPlugin.getSignerClient(context.TODO(), map[string]string{"aws-region": "us-west-2"})
+	assert.NoError(t, err)
+	usEast1, err := signerPlugin.getSignerClient(context.TODO(), map[string]string{"aws-region": "us-east-1"})
+	assert.NoError(t, err)
+	assert.NotSame(t, usWest2, usEast1, "client reused for different region")
+	actual, err := signerPlugin.getSignerClient(context.TODO(), map[string]string{"aws-region": "us-west-2", "aws-signer-revocation-failure-mode": "warn"})
+	assert.NoError(t, err)
+	assert.Same(t, usWest2, actual, "client not reused for same region")
+}
+
+func TestGetSignerClient_WithSignerAPI(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	signerPlugin := New(WithSignerAPI(client.NewMockInterface(mockCtrl)))
+	usWest2, err := signerPlugin.getSignerClient(context.TODO(), map[string]string{"aws-region": "us-west-2"})
+	assert.NoError(t, err)
+	usEast1, err := signerPlugin.getSignerClient(context.TODO(), map[string]string{"aws-region": "us-east-1"})
 	assert.NoError(t, err)
+	assert.Same(t, usWest2, usEast1, "client set through WithSignerAPI not used")
 }
 
 func convertCert(certs ...string) [][]byte {